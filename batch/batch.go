@@ -0,0 +1,239 @@
+package batch
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+	"github.com/anicine/anicine-scraper/store"
+)
+
+var logger = slog.Default().WithGroup("[BATCH]")
+
+// Job is one line of the input file, the anime info fields sit at the top level:
+//
+//	{"Title":"Sousou no Frieren","Type":"tv","MalID":52991,"SD":{"Year":2023,"Month":9,"Day":29},"Episodes":[1,2]}
+type Job struct {
+	models.AnimeInfo
+	Episodes []int `json:"Episodes"`
+	// Providers overrides Options.Scrape.Providers for this job.
+	Providers []string `json:"Providers,omitempty"`
+}
+
+// Output is one line of the output file, Line is the number of the input line it answers.
+type Output struct {
+	Line     int                     `json:"Line"`
+	MalID    int                     `json:"MalID,omitempty"`
+	Title    string                  `json:"Title,omitempty"`
+	Episodes []*scrape.EpisodeResult `json:"Episodes,omitempty"`
+	Failed   map[string]string       `json:"Failed,omitempty"`
+	Error    string                  `json:"Error,omitempty"`
+}
+
+// Options controls a batch run.
+type Options struct {
+	// Workers is the number of jobs running at the same time, default is 1.
+	Workers int
+	// Checkpoint is the file recording the finished lines, empty means the run is not resumable.
+	// The lines that failed are not recorded, so they run again on the next run.
+	Checkpoint string
+	// Scrape is passed to scrape.RunAll for every job.
+	Scrape *scrape.Options
+	// Store saves the embeds of every job with a MyAnimeList id when it is set.
+	Store *store.Store
+	// Incremental only scrapes the episodes missing from the store or older than MaxAge,
+	// a job without episodes then means every aired episode.
+	Incremental bool
+	// MaxAge is the age after which stored embeds are scraped again, zero means never.
+	MaxAge time.Duration
+}
+
+// Report counts what a batch run did.
+type Report struct {
+	Done    int
+	Skipped int
+	Failed  int
+}
+
+// Run reads the jobs from the input, scrapes them with a bounded pool of workers and writes
+// one output line per job as soon as it finishes. Lines already in the checkpoint are skipped,
+// so a run that was killed continues where it stopped when given the same checkpoint.
+// Jobs still running when the context is done are not written and run again on the next run.
+// Failed jobs are written but only checkpointed when their line is invalid, the others, such
+// as timeouts or blocked providers, run again and their new output line replaces the old one.
+func Run(ctx context.Context, in io.Reader, out io.Writer, opts *Options) (*Report, error) {
+	if opts == nil {
+		opts = &Options{}
+	}
+
+	check, err := openCheckpoint(opts.Checkpoint)
+	if err != nil {
+		return nil, err
+	}
+	defer check.close()
+
+	var (
+		wg      sync.WaitGroup
+		mutex   sync.Mutex
+		report  = new(Report)
+		werr    error
+		enc     = json.NewEncoder(out)
+		jobs    = make(chan *task)
+		workers = max(opts.Workers, 1)
+	)
+	enc.SetEscapeHTML(false)
+
+	// done writes the output then marks the line, a crash in between only repeats the line.
+	done := func(t *task, data *Output, err error) {
+		mutex.Lock()
+		defer mutex.Unlock()
+
+		if err != nil {
+			report.Failed++
+		} else {
+			report.Done++
+		}
+		if werr != nil {
+			return
+		}
+		if werr = enc.Encode(data); werr != nil {
+			return
+		}
+		// a bad line fails the same way every time, anything else is worth another try.
+		if err == nil || errors.Is(err, errs.ErrBadData) {
+			werr = check.mark(t.line)
+		}
+	}
+
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for t := range jobs {
+				data, err := run(ctx, t, opts)
+				if ctx.Err() != nil {
+					// the job may have been cut short, keep it for the next run.
+					continue
+				}
+				done(t, data, err)
+			}
+		}()
+	}
+
+	scanner := bufio.NewScanner(in)
+	scanner.Buffer(make([]byte, 64<<10), 16<<20)
+	line := 0
+	for scanner.Scan() {
+		line++
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		if check.finished(line) {
+			report.Skipped++
+			continue
+		}
+
+		t := &task{line: line, data: append([]byte(nil), scanner.Bytes()...)}
+		select {
+		case jobs <- t:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+	}
+	close(jobs)
+	wg.Wait()
+
+	if err = scanner.Err(); err != nil {
+		return report, err
+	}
+	if werr != nil {
+		return report, werr
+	}
+	if ctx.Err() != nil {
+		return report, context.Canceled
+	}
+
+	return report, nil
+}
+
+type task struct {
+	line int
+	data []byte
+}
+
+// run decodes the line and scrapes it, the error tells why the job produced no result.
+func run(ctx context.Context, t *task, x *Options) (*Output, error) {
+	data := &Output{Line: t.line}
+	fail := func(err error) (*Output, error) {
+		data.Error = err.Error()
+		return data, err
+	}
+
+	var job Job
+	if err := json.Unmarshal(t.data, &job); err != nil {
+		return fail(fmt.Errorf("%w: %w", errs.ErrBadData, err))
+	}
+	data.MalID = job.MalID
+	data.Title = job.Title
+
+	incremental := x.Incremental && x.Store != nil && job.MalID > 0
+	if job.Title == "" || (len(job.Episodes) == 0 && !incremental) {
+		return fail(fmt.Errorf("%w: the title and the episodes are required", errs.ErrBadData))
+	}
+	if job.Query == "" {
+		job.Query = analyze.CleanTitle(job.Title)
+	}
+
+	// every job gets its own copy, Restrict writes the plan into it.
+	opts := new(scrape.Options)
+	if x.Scrape != nil {
+		*opts = *x.Scrape
+	}
+	if len(job.Providers) > 0 {
+		opts.Providers = job.Providers
+	}
+	if incremental {
+		pending, err := x.Store.Restrict(&job.AnimeInfo, job.Episodes, opts, x.MaxAge)
+		if err != nil {
+			return fail(err)
+		}
+		if !pending {
+			logger.Info("job is up to date", "line", t.line, "title", job.Title)
+			return data, nil
+		}
+	}
+
+	logger.Info("job started", "line", t.line, "title", job.Title, "episodes", len(job.Episodes))
+	result, err := scrape.RunAll(ctx, &job.AnimeInfo, job.Episodes, opts)
+	if err != nil {
+		logger.Error("job failed", "line", t.line, "title", job.Title, "error", err)
+		return fail(err)
+	}
+
+	if x.Store != nil && job.MalID > 0 {
+		if err = x.Store.SaveResult(job.MalID, result); err != nil {
+			logger.Error("cannot store the job", "line", t.line, "title", job.Title, "error", err)
+		}
+	}
+
+	data.Episodes = result.Episodes
+	for k, v := range result.Errors {
+		if data.Failed == nil {
+			data.Failed = make(map[string]string)
+		}
+		data.Failed[k] = v.Error()
+	}
+	return data, nil
+}
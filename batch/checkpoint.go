@@ -0,0 +1,85 @@
+package batch
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// checkpoint is an append only file holding the number of every finished line,
+// it is synced after each write so a crash loses at most the line in flight.
+type checkpoint struct {
+	file  *os.File
+	done  map[int]bool
+	mutex sync.Mutex
+}
+
+func openCheckpoint(path string) (*checkpoint, error) {
+	c := &checkpoint{done: make(map[int]bool)}
+	if path == "" {
+		return c, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+
+	// a line cut by a crash has no newline, it is dropped so the next mark does not extend it
+	// and that line simply runs again.
+	size := bytes.LastIndexByte(data, '\n') + 1
+	scanner := bufio.NewScanner(bytes.NewReader(data[:size]))
+	for scanner.Scan() {
+		if n, err := strconv.Atoi(strings.TrimSpace(scanner.Text())); err == nil {
+			c.done[n] = true
+		}
+	}
+	if err = scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	c.file, err = os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	if size < len(data) {
+		if err = c.file.Truncate(int64(size)); err != nil {
+			c.file.Close()
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (c *checkpoint) finished(line int) bool {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	return c.done[line]
+}
+
+func (c *checkpoint) mark(line int) error {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	c.done[line] = true
+	if c.file == nil {
+		return nil
+	}
+	if _, err := fmt.Fprintln(c.file, line); err != nil {
+		return err
+	}
+	return c.file.Sync()
+}
+
+func (c *checkpoint) close() error {
+	if c.file == nil {
+		return nil
+	}
+	return c.file.Close()
+}
@@ -0,0 +1,60 @@
+package batch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckpoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "batch.checkpoint")
+
+	c, err := openCheckpoint(path)
+	require.NoError(t, err)
+	assert.False(t, c.finished(1))
+	require.NoError(t, c.mark(1))
+	require.NoError(t, c.mark(3))
+	assert.True(t, c.finished(1))
+	assert.False(t, c.finished(2))
+	require.NoError(t, c.close())
+
+	// a crash in the middle of writing 25 leaves a cut number behind
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	require.NoError(t, err)
+	_, err = file.WriteString("2")
+	require.NoError(t, err)
+	require.NoError(t, file.Close())
+
+	c, err = openCheckpoint(path)
+	require.NoError(t, err)
+	assert.True(t, c.finished(1))
+	assert.False(t, c.finished(2), "the cut line is not a finished one")
+	assert.True(t, c.finished(3))
+	assert.Len(t, c.done, 2)
+
+	// the next mark starts on its own line
+	require.NoError(t, c.mark(5))
+	require.NoError(t, c.close())
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "1\n3\n5\n", string(data))
+
+	c, err = openCheckpoint(path)
+	require.NoError(t, err)
+	defer c.close()
+	assert.True(t, c.finished(5))
+	assert.False(t, c.finished(25))
+	assert.Len(t, c.done, 3)
+}
+
+func TestCheckpointMemory(t *testing.T) {
+	c, err := openCheckpoint("")
+	require.NoError(t, err)
+	require.NoError(t, c.mark(7))
+	assert.True(t, c.finished(7))
+	assert.NoError(t, c.close())
+}
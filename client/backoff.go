@@ -0,0 +1,101 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+const (
+	attempts   = 10
+	minBackoff = 500 * time.Millisecond
+	maxBackoff = 1 * time.Minute
+)
+
+// RetryError is returned by Do when every attempt failed.
+type RetryError struct {
+	Link       string
+	StatusCode int
+	Attempts   int
+	Err        error
+}
+
+func (e *RetryError) Error() string {
+	msg := fmt.Sprintf("%s after %d attempts", errs.ErrNoData, e.Attempts)
+	if e.Err != nil {
+		msg += ": " + e.Err.Error()
+	}
+	return msg
+}
+
+// Unwrap keeps errors.Is working against errs.ErrNoData and the last transport error.
+func (e *RetryError) Unwrap() []error {
+	if e.Err == nil {
+		return []error{errs.ErrNoData}
+	}
+	return []error{errs.ErrNoData, e.Err}
+}
+
+// backoff returns the time to wait before the next attempt, it prefers the Retry-After
+// header when present and falls back to an exponential delay with full jitter.
+func backoff(attempt int, resp *http.Response) time.Duration {
+	if resp != nil {
+		if d, ok := retryAfter(resp.Header.Get("Retry-After")); ok {
+			return min(d, maxBackoff)
+		}
+	}
+
+	d := minBackoff << min(attempt, 10)
+	if resp != nil && resp.StatusCode == http.StatusTooManyRequests {
+		d *= 4
+	}
+	d = min(d, maxBackoff)
+
+	return d/2 + rand.N(d/2+1)
+}
+
+// retryAfter parses the Retry-After header as seconds or as an HTTP date.
+func retryAfter(value string) (time.Duration, bool) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(secs)*time.Second, 0), true
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return max(time.Until(date), 0), true
+	}
+	return 0, false
+}
+
+// retryable reports whether the status code is worth another attempt.
+func retryable(statusCode int) bool {
+	switch statusCode {
+	case http.StatusForbidden, http.StatusTooManyRequests, http.StatusRequestTimeout:
+		return true
+	}
+	return statusCode >= http.StatusInternalServerError
+}
+
+// sleep waits for the given duration or until the context is done.
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
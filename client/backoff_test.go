@@ -0,0 +1,95 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryAfter(t *testing.T) {
+	d, ok := retryAfter(" 120 ")
+	assert.True(t, ok)
+	assert.Equal(t, 2*time.Minute, d)
+
+	d, ok = retryAfter("-5")
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	d, ok = retryAfter(time.Now().Add(30 * time.Second).UTC().Format(http.TimeFormat))
+	assert.True(t, ok)
+	assert.InDelta(t, 30*time.Second, d, float64(2*time.Second))
+
+	d, ok = retryAfter("Sun, 06 Nov 1994 08:49:37 GMT")
+	assert.True(t, ok)
+	assert.Zero(t, d)
+
+	for _, v := range []string{"", "  ", "soon", "1.5"} {
+		_, ok = retryAfter(v)
+		assert.False(t, ok, v)
+	}
+}
+
+func TestBackoff(t *testing.T) {
+	resp := &http.Response{StatusCode: http.StatusServiceUnavailable, Header: http.Header{}}
+	for attempt := range 12 {
+		d := minBackoff << min(attempt, 10)
+		d = min(d, maxBackoff)
+		for range 20 {
+			got := backoff(attempt, resp)
+			assert.GreaterOrEqual(t, got, d/2)
+			assert.LessOrEqual(t, got, d)
+		}
+	}
+
+	// too many requests waits four times longer
+	resp.StatusCode = http.StatusTooManyRequests
+	for range 20 {
+		got := backoff(0, resp)
+		assert.GreaterOrEqual(t, got, 2*minBackoff)
+		assert.LessOrEqual(t, got, 4*minBackoff)
+	}
+
+	// the header wins, capped to the longest backoff
+	resp.Header.Set("Retry-After", "7")
+	assert.Equal(t, 7*time.Second, backoff(0, resp))
+	resp.Header.Set("Retry-After", "3600")
+	assert.Equal(t, maxBackoff, backoff(0, resp))
+
+	got := backoff(0, nil)
+	assert.GreaterOrEqual(t, got, minBackoff/2)
+	assert.LessOrEqual(t, got, minBackoff)
+}
+
+func TestRetryable(t *testing.T) {
+	for _, v := range []int{403, 408, 429, 500, 502, 503, 504} {
+		assert.True(t, retryable(v), v)
+	}
+	for _, v := range []int{200, 301, 400, 401, 404, 410} {
+		assert.False(t, retryable(v), v)
+	}
+}
+
+func TestRetryError(t *testing.T) {
+	cause := errors.New("connection reset")
+	err := &RetryError{Link: "https://example.com", StatusCode: 503, Attempts: 3, Err: cause}
+
+	assert.ErrorIs(t, err, errs.ErrNoData)
+	assert.ErrorIs(t, err, cause)
+	assert.Contains(t, err.Error(), "after 3 attempts: connection reset")
+
+	assert.ErrorIs(t, &RetryError{Attempts: 1}, errs.ErrNoData)
+}
+
+func TestSleep(t *testing.T) {
+	assert.NoError(t, sleep(context.Background(), 0))
+	assert.NoError(t, sleep(context.Background(), time.Millisecond))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, sleep(ctx, time.Hour), context.Canceled)
+}
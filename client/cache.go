@@ -0,0 +1,309 @@
+package client
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+var (
+	cache    *Cache
+	volatile = make(map[string]struct{})
+	vmutex   sync.RWMutex
+)
+
+// SetCache makes Do and Stream serve and store responses through the cache, nil turns it off.
+func SetCache(c *Cache) {
+	cache = c
+}
+
+// Volatile keeps the responses of the hosts and their sub domains out of the cache, for the
+// players that answer with signed links that expire quickly. A ttl set with SetTTL wins.
+func Volatile(hosts ...string) {
+	vmutex.Lock()
+	defer vmutex.Unlock()
+	for _, v := range hosts {
+		volatile[strings.ToLower(v)] = struct{}{}
+	}
+}
+
+// Cache is a content addressed disk cache of GET responses keyed by url. Fresh entries are served
+// without touching the network, stale ones are revalidated with If-None-Match and If-Modified-Since
+// when the site gave an ETag or a Last-Modified date. The Cache-Control of the site wins over the
+// ttl of the host: no-store keeps the response out, no-cache revalidates it every time and max-age
+// or Expires set how long it stays fresh.
+type Cache struct {
+	dir   string
+	ttl   time.Duration
+	ttls  map[string]time.Duration
+	mutex sync.RWMutex
+}
+
+// cacheEntry is the metadata stored next to every cached body.
+type cacheEntry struct {
+	key          string
+	Link         string    `json:"Link"`
+	Method       string    `json:"Method"`
+	ETag         string    `json:"ETag,omitempty"`
+	LastModified string    `json:"LastModified,omitempty"`
+	ContentType  string    `json:"ContentType,omitempty"`
+	Stored       time.Time `json:"Stored"`
+	Expires      time.Time `json:"Expires,omitempty"`
+	Revalidate   bool      `json:"Revalidate,omitempty"`
+}
+
+// NewCache creates a cache in the directory, ttl is how long entries stay fresh for hosts
+// without their own ttl.
+func NewCache(dir string, ttl time.Duration) (*Cache, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &Cache{
+		dir:  dir,
+		ttl:  ttl,
+		ttls: make(map[string]time.Duration),
+	}, nil
+}
+
+// SetTTL sets how long responses of the host and its sub domains stay fresh,
+// a negative ttl keeps the host out of the cache.
+func (c *Cache) SetTTL(host string, ttl time.Duration) {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+	c.ttls[strings.ToLower(host)] = ttl
+}
+
+// lifetime returns the ttl of the host, walking up the parent domains until one is found.
+// Volatile hosts without their own ttl get a negative one.
+func (c *Cache) lifetime(host string) time.Duration {
+	c.mutex.RLock()
+	defer c.mutex.RUnlock()
+	vmutex.RLock()
+	defer vmutex.RUnlock()
+
+	for h := strings.ToLower(host); h != ""; {
+		if ttl, ok := c.ttls[h]; ok {
+			return ttl
+		}
+		if _, ok := volatile[h]; ok {
+			return -1
+		}
+		_, h, _ = strings.Cut(h, ".")
+	}
+	return c.ttl
+}
+
+// cacheable reports whether the request may be served from and stored in the cache.
+func (c *Cache) cacheable(args *Args) bool {
+	if args.Method != "" && args.Method != http.MethodGet {
+		return false
+	}
+	return c.lifetime(args.Endpoint.Hostname()) >= 0
+}
+
+// key hashes the method, the url and the request body.
+func (c *Cache) key(method string, link *url.URL, body []byte) string {
+	h := sha256.New()
+	h.Write([]byte(method))
+	h.Write([]byte{0})
+	h.Write([]byte(link.String()))
+	h.Write([]byte{0})
+	h.Write(body)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (c *Cache) path(key, ext string) string {
+	return filepath.Join(c.dir, key[:2], key+ext)
+}
+
+// get returns the entry stored under the key, or nil if there is none.
+func (c *Cache) get(key string) *cacheEntry {
+	data, err := os.ReadFile(c.path(key, ".json"))
+	if err != nil {
+		return nil
+	}
+
+	entry := &cacheEntry{key: key}
+	if err = json.Unmarshal(data, entry); err != nil {
+		return nil
+	}
+	if _, err = os.Stat(c.path(key, ".body")); err != nil {
+		return nil
+	}
+	return entry
+}
+
+// fresh reports whether the entry can be served without asking the site.
+func (c *Cache) fresh(entry *cacheEntry, host string) bool {
+	ttl := c.lifetime(host)
+	switch {
+	case ttl < 0, entry.Revalidate:
+		return false
+	case !entry.Expires.IsZero():
+		return time.Now().Before(entry.Expires)
+	}
+	return ttl > 0 && time.Since(entry.Stored) < ttl
+}
+
+// control reads the Cache-Control and the Expires headers of the response into the entry.
+func (entry *cacheEntry) control(header http.Header) {
+	entry.Expires = time.Time{}
+	entry.Revalidate = false
+
+	var found bool
+	for _, v := range strings.Split(strings.ToLower(header.Get("Cache-Control")), ",") {
+		name, value, _ := strings.Cut(strings.TrimSpace(v), "=")
+		switch name {
+		case "no-cache":
+			entry.Revalidate = true
+		case "max-age":
+			if secs, err := strconv.Atoi(strings.Trim(value, `"`)); err == nil {
+				entry.Expires = entry.Stored.Add(time.Duration(max(secs, 0)) * time.Second)
+				found = true
+			}
+		}
+	}
+	if found {
+		return
+	}
+	if value := header.Get("Expires"); value != "" {
+		if date, err := http.ParseTime(value); err == nil {
+			entry.Expires = date
+		} else {
+			// an invalid date, such as 0, means already expired
+			entry.Expires = entry.Stored
+		}
+	}
+}
+
+// open returns the cached body as a response.
+func (c *Cache) open(entry *cacheEntry) (*http.Response, error) {
+	file, err := os.Open(c.path(entry.key, ".body"))
+	if err != nil {
+		return nil, err
+	}
+
+	header := make(http.Header)
+	if entry.ContentType != "" {
+		header.Set("Content-Type", entry.ContentType)
+	}
+	return &http.Response{
+		Status:     http.StatusText(http.StatusOK),
+		StatusCode: http.StatusOK,
+		Header:     header,
+		Body:       file,
+	}, nil
+}
+
+// touch marks a revalidated entry as fresh again with the headers of the 304 answer.
+func (c *Cache) touch(entry *cacheEntry, header http.Header) {
+	entry.Stored = time.Now()
+	entry.control(header)
+	if err := c.meta(entry); err != nil {
+		logger.Warn("cannot update cache entry", "link", entry.Link, "error", err)
+	}
+}
+
+func (c *Cache) meta(entry *cacheEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	tmp := c.path(entry.key, ".json.tmp")
+	if err = os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, c.path(entry.key, ".json"))
+}
+
+// store writes the response body to the cache and returns a response that reads it back
+// from disk, so the body is never held in memory.
+func (c *Cache) store(key string, args *Args, resp *http.Response, limit int64) (*http.Response, error) {
+	defer resp.Body.Close()
+
+	if err := os.MkdirAll(filepath.Dir(c.path(key, "")), 0o755); err != nil {
+		return nil, err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(c.path(key, "")), key+".*.tmp")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	n, err := io.Copy(tmp, io.LimitReader(resp.Body, limit+1))
+	if cerr := tmp.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return nil, err
+	}
+	if n > limit {
+		return nil, ErrTooLarge
+	}
+	if err = os.Rename(tmp.Name(), c.path(key, ".body")); err != nil {
+		return nil, err
+	}
+
+	entry := &cacheEntry{
+		key:          key,
+		Link:         errs.Redact(args.Endpoint.String()),
+		Method:       args.Method,
+		ETag:         resp.Header.Get("ETag"),
+		LastModified: resp.Header.Get("Last-Modified"),
+		ContentType:  resp.Header.Get("Content-Type"),
+		Stored:       time.Now(),
+	}
+	entry.control(resp.Header)
+	if err = c.meta(entry); err != nil {
+		return nil, err
+	}
+
+	return c.open(entry)
+}
+
+// storable reports whether the response may be written to the cache.
+func storable(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusOK {
+		return false
+	}
+	return !strings.Contains(strings.ToLower(resp.Header.Get("Cache-Control")), "no-store")
+}
+
+// Purge removes every entry stored before the given time.
+func (c *Cache) Purge(before time.Time) error {
+	return filepath.WalkDir(c.dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() || !strings.HasSuffix(path, ".json") {
+			return err
+		}
+
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		var entry cacheEntry
+		if err = json.Unmarshal(data, &entry); err != nil || entry.Stored.Before(before) {
+			key := strings.TrimSuffix(filepath.Base(path), ".json")
+			if err = os.Remove(c.path(key, ".body")); err != nil && !errors.Is(err, fs.ErrNotExist) {
+				return err
+			}
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
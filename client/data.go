@@ -7,7 +7,10 @@ import (
 	"net/url"
 )
 
-const UserAgent = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+const (
+	UserAgent    = "Mozilla/5.0 (X11; Linux x86_64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/122.0.0.0 Safari/537.36"
+	DefaultLimit = 64 << 20
+)
 
 var (
 	logger = slog.Default().WithGroup("[HTTP]")
@@ -26,10 +29,20 @@ type Args struct {
 	Endpoint *url.URL
 	Headers  map[string]string
 	Body     io.Reader
-	cookies  []*http.Cookie
+	// Limit caps the size of the response body, zero means DefaultLimit.
+	Limit   int64
+	cookies []*http.Cookie
 }
 
 // return the cookies of the response after the Args get passed to the 'Do' function.
 func (x *Args) Cookies() []*http.Cookie {
 	return x.cookies
 }
+
+// limit returns the size cap of the response body.
+func (x *Args) limit() int64 {
+	if x.Limit > 0 {
+		return x.Limit
+	}
+	return DefaultLimit
+}
@@ -3,39 +3,35 @@ package client
 import (
 	"bytes"
 	"context"
+	"errors"
 	"io"
 	"net/http"
 	"net/url"
+	"strings"
 	"time"
 
 	"github.com/anicine/anicine-scraper/internal/errs"
 )
 
-// reader copies the request body into a new buffer for potential reuse.
-func reader(body io.Reader) io.Reader {
+// payload reads the request body once so every attempt can send it again.
+func payload(body io.Reader) ([]byte, error) {
 	if body == nil {
-		return nil
-	}
-
-	var buf *bytes.Buffer
-	switch t := body.(type) {
-	case *bytes.Buffer:
-		buf = t
-	default:
-		buf = new(bytes.Buffer)
-		_, err := io.Copy(buf, body)
-		if err != nil {
-			logger.Error("cannot copying request body", "error", err)
-			return nil
-		}
+		return nil, nil
 	}
-
-	return bytes.NewReader(buf.Bytes())
+	if buf, ok := body.(*bytes.Buffer); ok {
+		return buf.Bytes(), nil
+	}
+	return io.ReadAll(body)
 }
 
 // request initializes a new HTTP request with the given arguments.
-func request(args *Args) (*http.Request, error) {
-	req, err := http.NewRequest(args.Method, args.Endpoint.String(), reader(args.Body))
+func request(ctx context.Context, args *Args, body []byte) (*http.Request, error) {
+	var data io.Reader
+	if body != nil {
+		data = bytes.NewReader(body)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, args.Method, args.Endpoint.String(), data)
 	if err != nil {
 		return nil, err
 	}
@@ -44,6 +40,11 @@ func request(args *Args) (*http.Request, error) {
 	for k, v := range args.Headers {
 		req.Header.Add(k, v)
 	}
+	if jar := cookies(args.Endpoint.Hostname()); jar != nil {
+		for _, v := range jar.Cookies(args.Endpoint) {
+			req.AddCookie(v)
+		}
+	}
 	return req, nil
 }
 
@@ -57,105 +58,275 @@ func redirect(resp *http.Response, args *Args) error {
 	return nil
 }
 
-// retry handles retries for specific status codes.
-func retry(statusCode int, client **http.Client) time.Duration {
-	switch statusCode {
-	case http.StatusForbidden:
-		if *client == http.DefaultClient {
-			return 15 * time.Second
-		}
-		*client = http.DefaultClient
-		return 0
-	case http.StatusTooManyRequests:
-		if *client == http.DefaultClient {
-			return 1 * time.Minute
-		}
-		return 15 * time.Second
+// challenge reports whether the response is a Cloudflare block or challenge page.
+func challenge(resp *http.Response) bool {
+	if resp.StatusCode != http.StatusForbidden && resp.StatusCode != http.StatusServiceUnavailable && resp.StatusCode != http.StatusTooManyRequests {
+		return false
 	}
-	return 0
+	if resp.Header.Get("Cf-Mitigated") != "" {
+		return true
+	}
+	return strings.Contains(strings.ToLower(resp.Header.Get("Server")), "cloudflare")
 }
 
-// clean handles closer for both the request and response bodies.
-func clean(req *http.Request, resp *http.Response) {
-	if req != nil && req.Body != nil {
-		req.Body.Close()
+// redact hides the credentials of the link net/http puts in its errors, see errs.Redact.
+func redact(err error) error {
+	var e *url.Error
+	if errors.As(err, &e) {
+		e.URL = errs.Redact(e.URL)
 	}
-	if resp != nil && resp.Body != nil {
-		resp.Body.Close()
+	return err
+}
+
+// discard drains a little of the body so the connection can be reused, then closes it.
+func discard(resp *http.Response) {
+	if resp == nil || resp.Body == nil {
+		return
 	}
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64<<10))
+	resp.Body.Close()
 }
 
-// agent handles which client should do the request.
-func agent(retries int, args *Args) *http.Client {
-	if retries == 0 {
-		if args.Proxy {
-			return proxy
+// agent handles which client should do the request. When every proxy of the pool is ejected
+// it waits for the first one to come back, the request never goes out without a proxy.
+func agent(ctx context.Context, args *Args) (*http.Client, *node, error) {
+	if !args.Proxy {
+		return http.DefaultClient, nil, nil
+	}
+	if pool == nil {
+		return proxy, nil, nil
+	}
+
+	for {
+		n, back := pool.next(args.Endpoint.Host)
+		if n != nil {
+			return n.client, n, nil
+		}
+
+		wait := time.Until(back)
+		if wait > minCooldown {
+			logger.Error("every proxy is ejected", "host", args.Endpoint.Host, "back", wait)
+			return nil, nil, errs.HTTP(args.Endpoint.String(), 0, errors.Join(errs.ErrBlocked, ErrNoProxy))
+		}
+		logger.Warn("every proxy is ejected", "host", args.Endpoint.Host, "wait", wait)
+		if err := sleep(ctx, wait); err != nil {
+			return nil, nil, err
 		}
-	} else if retries > 7 {
-		return http.DefaultClient
 	}
-	return http.DefaultClient
 }
 
-// Do executes the HTTP request with retry and redirection handling.
+// Do executes the HTTP request with retry and redirection handling and returns the whole body.
+// Failed attempts are retried with a jittered exponential backoff that honours the Retry-After header,
+// answers that another attempt would not change, such as 401 or 410, fail at once.
 func Do(ctx context.Context, args *Args) (io.Reader, error) {
+	body, err := Stream(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		logger.Error("cannot read response body", "link", errs.Redact(args.Endpoint.String()), "error", err)
+		return nil, errs.HTTP(args.Endpoint.String(), http.StatusOK, errors.Join(errs.ErrNoData, err))
+	}
+
+	return bytes.NewReader(data), nil
+}
+
+// Stream executes the HTTP request like Do but hands back the response body as it arrives.
+// Reading more than Args.Limit bytes fails with ErrTooLarge, the caller must close the body.
+func Stream(ctx context.Context, args *Args) (io.ReadCloser, error) {
+	resp, release, err := do(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+
+	return &stream{
+		body:    resp.Body,
+		left:    args.limit(),
+		release: release,
+	}, nil
+}
+
+// do runs the retry loop and returns the first successful response with its body still open.
+// The returned function frees the host slot and is called when the body gets closed.
+func do(ctx context.Context, args *Args) (*http.Response, func(), error) {
 	if args.Endpoint == nil {
-		return nil, errs.ErrBadData
+		return nil, nil, errs.ErrBadData
+	}
+
+	body, err := payload(args.Body)
+	if err != nil {
+		logger.Error("cannot copying request body", "error", err)
+		return nil, nil, errs.ErrBadData
 	}
 
 	var (
-		req    *http.Request
-		resp   *http.Response
-		client *http.Client
-		err    error
+		key   string
+		entry *cacheEntry
+	)
+	// responses of session sites depend on their cookies, so they never go through the cache
+	if cache != nil && cache.cacheable(args) && cookies(args.Endpoint.Hostname()) == nil {
+		key = cache.key(args.Method, args.Endpoint, body)
+		if entry = cache.get(key); entry != nil && cache.fresh(entry, args.Endpoint.Hostname()) {
+			if resp, err := cache.open(entry); err == nil {
+				logger.Info("served from cache", "host", args.Endpoint.Host, "link", args.Endpoint.Path)
+				return resp, func() {}, nil
+			}
+		}
+	}
+
+	var (
+		req     *http.Request
+		resp    *http.Response
+		client  *http.Client
+		through *node
+		release func()
+		status  int
+		blocked bool
 	)
 
 	// Retry loop for handling failures
-	for i := 0; i < 10; i++ {
+	for i := 0; i < attempts; i++ {
 		select {
 		case <-ctx.Done():
-			return nil, context.Canceled
+			return nil, nil, ctx.Err()
 		default:
-			client = agent(i, args)
-			req, err = request(args)
+			client, through, err = agent(ctx, args)
+			if err != nil {
+				return nil, nil, err
+			}
+			if recorder != nil {
+				client = recorder.client(client)
+			}
+			req, err = request(ctx, args, body)
 			if err != nil {
-				logger.Error("cannot create request", "link", args.Endpoint, "error", err)
+				logger.Error("cannot create request", "link", errs.Redact(args.Endpoint.String()), "error", err)
 				continue
 			}
+			if entry != nil {
+				if entry.ETag != "" {
+					req.Header.Set("If-None-Match", entry.ETag)
+				}
+				if entry.LastModified != "" {
+					req.Header.Set("If-Modified-Since", entry.LastModified)
+				}
+			}
+
+			host := limit(args.Endpoint.Host)
+			release, err = host.wait(ctx)
+			if err != nil {
+				return nil, nil, err
+			}
 
 			resp, err = client.Do(req)
 			if err != nil {
-				logger.Error("cannot get response", "link", args.Endpoint, "error", err)
+				err = redact(err)
+				release()
+				if ctx.Err() != nil {
+					return nil, nil, ctx.Err()
+				}
+				if errors.Is(err, ErrNoInteraction) {
+					return nil, nil, errs.HTTP(args.Endpoint.String(), 0, errors.Join(errs.ErrNoData, err))
+				}
+				if through != nil {
+					pool.report(through, nil, err)
+				}
+				logger.Error("cannot get response", "link", errs.Redact(args.Endpoint.String()), "error", err)
+				if err = sleep(ctx, backoff(i, nil)); err != nil {
+					return nil, nil, err
+				}
 				continue
 			}
-			defer clean(req, resp)
+
+			status = resp.StatusCode
+			blocked = challenge(resp)
+			jar := cookies(args.Endpoint.Hostname())
+			if jar == nil && blocked {
+				// a host behind a challenge keeps its session from now on, the clearance
+				// cookies it hands out or the ones loaded from COOKIE_FILE are sent back
+				SetJar(nil, site(args.Endpoint.Hostname()))
+				jar = defaultJar
+			}
+			if jar != nil {
+				jar.SetCookies(args.Endpoint, resp.Cookies())
+			}
+			if through != nil {
+				pool.report(through, resp, nil)
+			}
 
 			logger.Info("accepted response", "proxy", args.Proxy, "code", resp.StatusCode, "host", args.Endpoint.Host, "link", args.Endpoint.Path)
 
 			switch resp.StatusCode {
-			case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
-				return nil, errs.ErrBadData
 			case http.StatusOK, http.StatusNotModified:
 				args.cookies = resp.Cookies()
-				if body := reader(resp.Body); body != nil {
-					return body, nil
+				if entry != nil && resp.StatusCode == http.StatusNotModified {
+					discard(resp)
+					release()
+					cache.touch(entry, resp.Header)
+					cached, err := cache.open(entry)
+					if err != nil {
+						return nil, nil, errs.HTTP(args.Endpoint.String(), http.StatusNotModified, errors.Join(errs.ErrNoData, err))
+					}
+					return cached, func() {}, nil
+				}
+				if key != "" && storable(resp) {
+					cached, err := cache.store(key, args, resp, args.limit())
+					release()
+					if err != nil {
+						logger.Error("cannot cache response", "link", errs.Redact(args.Endpoint.String()), "error", err)
+						return nil, nil, errs.HTTP(args.Endpoint.String(), http.StatusOK, errors.Join(errs.ErrNoData, err))
+					}
+					return cached, func() {}, nil
 				}
-				return nil, errs.ErrNoData
+				return resp, release, nil
+			}
+
+			// every other answer is a failed attempt, so give the connection back right away
+			discard(resp)
+			release()
+
+			switch resp.StatusCode {
+			case http.StatusPreconditionFailed, http.StatusPreconditionRequired:
+				return nil, nil, errs.HTTP(args.Endpoint.String(), resp.StatusCode, errs.ErrBadData)
 			case http.StatusNotFound, http.StatusBadRequest:
-				return nil, errs.ErrNotFound
+				return nil, nil, errs.HTTP(args.Endpoint.String(), resp.StatusCode, errs.ErrNotFound)
 			case http.StatusFound, http.StatusMovedPermanently:
 				if err = redirect(resp, args); err != nil {
-					return nil, err
+					return nil, nil, err
 				}
 				continue
 			}
-			if retryDuration := retry(resp.StatusCode, &client); retryDuration > 0 {
-				time.Sleep(retryDuration)
+
+			if !retryable(resp.StatusCode) {
+				if resp.StatusCode == http.StatusGone {
+					return nil, nil, errs.HTTP(args.Endpoint.String(), resp.StatusCode, errs.ErrNotFound)
+				}
+				return nil, nil, errs.HTTP(args.Endpoint.String(), resp.StatusCode, errs.ErrNoData)
+			}
+			wait := backoff(i, resp)
+			logger.Warn("backing off", "code", resp.StatusCode, "host", args.Endpoint.Host, "attempt", i+1, "wait", wait)
+			if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusForbidden {
+				// the whole host is throttled, so hold back every request to it
+				host.pause(wait)
 				continue
 			}
+			if err = sleep(ctx, wait); err != nil {
+				return nil, nil, err
+			}
 		}
 	}
 
-	logger.Error("failed to complete the operation", "link", args.Endpoint, "error", err)
-	return nil, errs.ErrNoData
+	if blocked {
+		err = errs.ErrBlocked
+	}
+
+	logger.Error("failed to complete the operation", "link", errs.Redact(args.Endpoint.String()), "code", status, "error", err)
+	return nil, nil, errs.HTTP(args.Endpoint.String(), status, &RetryError{
+		Link:       errs.Redact(args.Endpoint.String()),
+		StatusCode: status,
+		Attempts:   attempts,
+		Err:        err,
+	})
 }
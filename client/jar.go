@@ -0,0 +1,327 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+var (
+	jars       = make(map[string]http.CookieJar)
+	defaultJar = NewJar()
+	jmutex     sync.RWMutex
+)
+
+// Jar is a cookie jar that keeps one session per site, a site being the host without
+// its sub domains. Cookies follow their Domain and Path like in a browser: a cookie without
+// a domain only goes back to the host that set it, one with a domain to that domain and its
+// sub domains. It can be saved to and loaded from disk to keep sessions across runs.
+type Jar struct {
+	sites map[string]map[string]*jarEntry
+	mutex sync.RWMutex
+}
+
+// jarEntry is a stored cookie, its Domain is always set and HostOnly tells whether it
+// came without one.
+type jarEntry struct {
+	http.Cookie
+	HostOnly bool
+}
+
+// NewJar creates an empty jar.
+func NewJar() *Jar {
+	return &Jar{
+		sites: make(map[string]map[string]*jarEntry),
+	}
+}
+
+// site returns the host without its sub domains.
+func site(host string) string {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	labels := strings.Split(host, ".")
+	if len(labels) <= 2 {
+		return host
+	}
+	return strings.Join(labels[len(labels)-2:], ".")
+}
+
+// domainMatch reports whether the host is the domain or one of its sub domains.
+func domainMatch(host, domain string) bool {
+	return host == domain || strings.HasSuffix(host, "."+domain)
+}
+
+// pathMatch reports whether the cookie path covers the path of the request.
+func pathMatch(path, cookie string) bool {
+	if path == cookie {
+		return true
+	}
+	return strings.HasPrefix(path, cookie) && (strings.HasSuffix(cookie, "/") || path[len(cookie)] == '/')
+}
+
+// defaultPath is the path of a cookie set without one, the directory of the request path.
+func defaultPath(path string) string {
+	i := strings.LastIndex(path, "/")
+	if i <= 0 {
+		return "/"
+	}
+	return path[:i]
+}
+
+// put stores the entry, an expired one removes the cookie it replaces. The caller must hold
+// the mutex.
+func (j *Jar) put(e *jarEntry, now time.Time) {
+	key := site(e.Domain)
+	data, ok := j.sites[key]
+	if !ok {
+		data = make(map[string]*jarEntry)
+		j.sites[key] = data
+	}
+
+	id := e.Name + ";" + e.Domain + ";" + e.Path
+	if e.MaxAge < 0 || (!e.Expires.IsZero() && e.Expires.Before(now)) {
+		delete(data, id)
+		return
+	}
+	data[id] = e
+}
+
+func (j *Jar) SetCookies(u *url.URL, cookies []*http.Cookie) {
+	if u == nil || len(cookies) == 0 {
+		return
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	var (
+		now  = time.Now()
+		host = strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+	)
+	for _, v := range cookies {
+		if v == nil || v.Name == "" {
+			continue
+		}
+
+		e := &jarEntry{Cookie: *v}
+		e.Domain = strings.ToLower(strings.TrimPrefix(v.Domain, "."))
+		switch {
+		case e.Domain == "":
+			e.Domain, e.HostOnly = host, true
+		case !domainMatch(host, e.Domain):
+			// a site cannot set cookies for another one
+			continue
+		}
+		if !strings.HasPrefix(e.Path, "/") {
+			e.Path = defaultPath(u.EscapedPath())
+		}
+		if e.MaxAge > 0 {
+			e.Expires = now.Add(time.Duration(e.MaxAge) * time.Second)
+		}
+		j.put(e, now)
+	}
+}
+
+func (j *Jar) Cookies(u *url.URL) []*http.Cookie {
+	if u == nil {
+		return nil
+	}
+
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	var (
+		now     = time.Now()
+		host    = strings.ToLower(strings.TrimSuffix(u.Hostname(), "."))
+		path    = u.EscapedPath()
+		entries []*jarEntry
+	)
+	if path == "" {
+		path = "/"
+	}
+	for _, v := range j.sites[site(host)] {
+		if !v.Expires.IsZero() && v.Expires.Before(now) {
+			continue
+		}
+		if v.Secure && u.Scheme != "https" {
+			continue
+		}
+		if v.HostOnly && host != v.Domain || !v.HostOnly && !domainMatch(host, v.Domain) {
+			continue
+		}
+		if !pathMatch(path, v.Path) {
+			continue
+		}
+		entries = append(entries, v)
+	}
+
+	// the most specific paths go first, like browsers send them
+	slices.SortFunc(entries, func(a, b *jarEntry) int {
+		if n := len(b.Path) - len(a.Path); n != 0 {
+			return n
+		}
+		return strings.Compare(a.Name, b.Name)
+	})
+
+	cookies := make([]*http.Cookie, 0, len(entries))
+	for _, v := range entries {
+		cookies = append(cookies, &http.Cookie{Name: v.Name, Value: v.Value})
+	}
+	return cookies
+}
+
+// Sites returns the sites the jar holds cookies for.
+func (j *Jar) Sites() []string {
+	j.mutex.RLock()
+	defer j.mutex.RUnlock()
+
+	var sites []string
+	for k, v := range j.sites {
+		if len(v) > 0 {
+			sites = append(sites, k)
+		}
+	}
+	slices.Sort(sites)
+	return sites
+}
+
+// jarCookie is the on disk form of a cookie.
+type jarCookie struct {
+	Name     string    `json:"Name"`
+	Value    string    `json:"Value"`
+	Domain   string    `json:"Domain,omitempty"`
+	Path     string    `json:"Path,omitempty"`
+	HostOnly bool      `json:"HostOnly,omitempty"`
+	Secure   bool      `json:"Secure,omitempty"`
+	Expires  time.Time `json:"Expires,omitempty"`
+}
+
+// Save writes the jar to the file as JSON, session cookies included.
+func (j *Jar) Save(path string) error {
+	j.mutex.RLock()
+	data := make(map[string][]jarCookie, len(j.sites))
+	for k, v := range j.sites {
+		for _, c := range v {
+			data[k] = append(data[k], jarCookie{
+				Name:     c.Name,
+				Value:    c.Value,
+				Domain:   c.Domain,
+				Path:     c.Path,
+				HostOnly: c.HostOnly,
+				Secure:   c.Secure,
+				Expires:  c.Expires,
+			})
+		}
+	}
+	j.mutex.RUnlock()
+
+	body, err := json.MarshalIndent(data, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, body, 0o600); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// Load reads the cookies saved by Save into the jar, a missing file is not an error.
+// A cookie written by hand without a domain or a path goes to the whole site.
+func (j *Jar) Load(path string) error {
+	body, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil
+		}
+		return err
+	}
+
+	var data map[string][]jarCookie
+	if err = json.Unmarshal(body, &data); err != nil {
+		return err
+	}
+
+	j.mutex.Lock()
+	defer j.mutex.Unlock()
+
+	now := time.Now()
+	for k, v := range data {
+		for _, c := range v {
+			if c.Name == "" {
+				continue
+			}
+			e := &jarEntry{
+				Cookie: http.Cookie{
+					Name:    c.Name,
+					Value:   c.Value,
+					Domain:  strings.ToLower(strings.TrimPrefix(c.Domain, ".")),
+					Path:    c.Path,
+					Secure:  c.Secure,
+					Expires: c.Expires,
+				},
+				HostOnly: c.HostOnly && c.Domain != "",
+			}
+			if e.Domain == "" || site(e.Domain) != site(k) {
+				e.Domain, e.HostOnly = site(k), false
+			}
+			if !strings.HasPrefix(e.Path, "/") {
+				e.Path = "/"
+			}
+			j.put(e, now)
+		}
+	}
+	return nil
+}
+
+// DefaultJar returns the jar used by the hosts registered with a nil jar.
+func DefaultJar() *Jar {
+	return defaultJar
+}
+
+// SetJar makes the hosts and their sub domains keep their cookies in the jar,
+// a nil jar means the default one.
+func SetJar(jar http.CookieJar, hosts ...string) {
+	if jar == nil {
+		jar = defaultJar
+	}
+
+	jmutex.Lock()
+	defer jmutex.Unlock()
+	for _, v := range hosts {
+		jars[strings.ToLower(v)] = jar
+	}
+}
+
+// Cookies returns the cookies the jar of the host would send to the link.
+func Cookies(link *url.URL) []*http.Cookie {
+	if jar := cookies(link.Hostname()); jar != nil {
+		return jar.Cookies(link)
+	}
+	return nil
+}
+
+// cookies returns the jar of the host, walking up the parent domains until one is found.
+func cookies(host string) http.CookieJar {
+	jmutex.RLock()
+	defer jmutex.RUnlock()
+
+	for h := strings.ToLower(host); h != ""; {
+		if jar, ok := jars[h]; ok {
+			return jar
+		}
+		_, h, _ = strings.Cut(h, ".")
+	}
+	return nil
+}
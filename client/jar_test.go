@@ -0,0 +1,96 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func names(cookies []*http.Cookie) []string {
+	var data []string
+	for _, v := range cookies {
+		data = append(data, v.Name+"="+v.Value)
+	}
+	return data
+}
+
+func link(raw string) *url.URL {
+	u, _ := url.Parse(raw)
+	return u
+}
+
+func TestJarDomain(t *testing.T) {
+	j := NewJar()
+	j.SetCookies(link("https://www.animeunity.to/anime/1"), []*http.Cookie{
+		{Name: "host", Value: "1"},
+		{Name: "site", Value: "2", Domain: ".animeunity.to", Path: "/"},
+		{Name: "other", Value: "3", Domain: "example.com"},
+	})
+
+	assert.Equal(t, []string{"host=1", "site=2"}, names(j.Cookies(link("https://www.animeunity.to/anime/2"))))
+	assert.Equal(t, []string{"site=2"}, names(j.Cookies(link("https://animeunity.to/"))), "a cookie without a domain stays on its host")
+	assert.Equal(t, []string{"site=2"}, names(j.Cookies(link("https://cdn.animeunity.to/"))))
+	assert.Empty(t, j.Cookies(link("https://example.com/")), "a site cannot set cookies for another one")
+}
+
+func TestJarPath(t *testing.T) {
+	j := NewJar()
+	j.SetCookies(link("https://example.com/anime/frieren/episode"), []*http.Cookie{
+		{Name: "default", Value: "1"},
+		{Name: "api", Value: "2", Path: "/api"},
+		{Name: "root", Value: "3", Path: "/"},
+	})
+
+	assert.Equal(t, []string{"default=1", "root=3"}, names(j.Cookies(link("https://example.com/anime/frieren/2"))))
+	assert.Equal(t, []string{"root=3"}, names(j.Cookies(link("https://example.com/anime/frierenx"))))
+	assert.Equal(t, []string{"api=2", "root=3"}, names(j.Cookies(link("https://example.com/api/search"))))
+	assert.Equal(t, []string{"root=3"}, names(j.Cookies(link("https://example.com/apis"))))
+	assert.Equal(t, []string{"root=3"}, names(j.Cookies(link("https://example.com"))))
+}
+
+func TestJarExpiry(t *testing.T) {
+	j := NewJar()
+	u := link("http://example.com/")
+	j.SetCookies(u, []*http.Cookie{
+		{Name: "a", Value: "1"},
+		{Name: "b", Value: "2", MaxAge: 60},
+		{Name: "c", Value: "3", Expires: time.Now().Add(-time.Hour)},
+		{Name: "d", Value: "4", Secure: true},
+	})
+	assert.Equal(t, []string{"a=1", "b=2"}, names(j.Cookies(u)), "expired and secure cookies are left out")
+
+	j.SetCookies(u, []*http.Cookie{{Name: "a", MaxAge: -1}, {Name: "b", Value: "5"}})
+	assert.Equal(t, []string{"b=5"}, names(j.Cookies(u)))
+}
+
+func TestJarSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cookies.json")
+
+	j := NewJar()
+	j.SetCookies(link("https://www.animeunity.to/"), []*http.Cookie{
+		{Name: "XSRF-TOKEN", Value: "x"},
+		{Name: "session", Value: "s", Domain: "animeunity.to", Path: "/", MaxAge: 3600},
+	})
+	require.NoError(t, j.Save(path))
+
+	loaded := NewJar()
+	require.NoError(t, loaded.Load(path))
+	assert.Equal(t, []string{"XSRF-TOKEN=x", "session=s"}, names(loaded.Cookies(link("https://www.animeunity.to/"))))
+	assert.Equal(t, []string{"session=s"}, names(loaded.Cookies(link("https://animeunity.to/"))))
+
+	// a cookie added by hand goes to the whole site
+	require.NoError(t, os.WriteFile(path, []byte(`{"witanime.one":[{"Name":"cf_clearance","Value":"c"}]}`), 0o600))
+	loaded = NewJar()
+	require.NoError(t, loaded.Load(path))
+	assert.Equal(t, []string{"witanime.one"}, loaded.Sites())
+	assert.Equal(t, []string{"cf_clearance=c"}, names(loaded.Cookies(link("https://witanime.one/episode/1/"))))
+	assert.Equal(t, []string{"cf_clearance=c"}, names(loaded.Cookies(link("https://www.witanime.one/"))))
+
+	require.NoError(t, NewJar().Load(filepath.Join(t.TempDir(), "missing.json")))
+}
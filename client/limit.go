@@ -0,0 +1,183 @@
+package client
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Policy describes how politely a host should be queried. The resources set the policies
+// of their APIs when they load and the configuration can override them, see SetPolicy.
+type Policy struct {
+	// Rate is the number of requests per second allowed for the host.
+	Rate float64
+	// Burst is the number of requests that can be sent at once before the rate applies.
+	Burst int
+	// Concurrency caps the number of requests in flight for the host.
+	Concurrency int
+}
+
+var (
+	defaultPolicy = Policy{Rate: 10, Burst: 2, Concurrency: 4}
+	policies      = make(map[string]Policy)
+	limiters      = make(map[string]*limiter)
+	lmutex        sync.Mutex
+)
+
+// SetDefaultPolicy sets the policy used for every host without its own policy.
+func SetDefaultPolicy(p Policy) {
+	lmutex.Lock()
+	defer lmutex.Unlock()
+	defaultPolicy = p
+	renew()
+}
+
+// SetPolicy sets the policy of the host and its sub domains.
+func SetPolicy(host string, p Policy) {
+	lmutex.Lock()
+	defer lmutex.Unlock()
+	policies[strings.ToLower(host)] = p
+	renew()
+}
+
+// renew applies the policies to the limiters already in use, the requests in flight keep
+// their slots. The caller must hold lmutex.
+func renew() {
+	for host, l := range limiters {
+		l.apply(policy(host))
+	}
+}
+
+// policy returns the policy of the host, walking up the parent domains until one is found.
+func policy(host string) Policy {
+	for h := host; h != ""; {
+		if p, ok := policies[h]; ok {
+			return p
+		}
+		_, h, _ = strings.Cut(h, ".")
+	}
+	return defaultPolicy
+}
+
+// limit returns the limiter of the host, creating it if needed.
+func limit(host string) *limiter {
+	host = strings.ToLower(host)
+
+	lmutex.Lock()
+	defer lmutex.Unlock()
+	if l, ok := limiters[host]; ok {
+		return l
+	}
+
+	l := newLimiter(policy(host))
+	limiters[host] = l
+	return l
+}
+
+// limiter is a token bucket paired with a concurrency cap.
+type limiter struct {
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+	until  time.Time
+	slots  chan struct{}
+	mutex  sync.Mutex
+}
+
+func newLimiter(p Policy) *limiter {
+	l := &limiter{last: time.Now()}
+	l.apply(p)
+	l.tokens = l.burst
+	return l
+}
+
+// apply changes the policy of the limiter. A new concurrency cap only holds back the
+// requests that start after it, the ones in flight release the slots they took.
+func (l *limiter) apply(p Policy) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.rate = p.Rate
+	l.burst = float64(max(p.Burst, 1))
+	l.tokens = min(l.tokens, l.burst)
+	if cap(l.slots) == max(p.Concurrency, 0) {
+		return
+	}
+	l.slots = nil
+	if p.Concurrency > 0 {
+		l.slots = make(chan struct{}, p.Concurrency)
+	}
+}
+
+// reserve takes a token and returns how long the caller has to wait before using it.
+func (l *limiter) reserve() time.Duration {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	if now.Before(l.until) {
+		return l.until.Sub(now)
+	}
+	if l.rate <= 0 {
+		return 0
+	}
+
+	l.tokens = min(l.burst, l.tokens+now.Sub(l.last).Seconds()*l.rate)
+	l.last = now
+	if l.tokens >= 1 {
+		l.tokens--
+		return 0
+	}
+
+	return time.Duration((1 - l.tokens) / l.rate * float64(time.Second))
+}
+
+// wait blocks until the host allows a new request and a concurrency slot is free.
+// The returned function releases the slot.
+func (l *limiter) wait(ctx context.Context) (func(), error) {
+	for {
+		d := l.reserve()
+		if d <= 0 {
+			break
+		}
+
+		timer := time.NewTimer(d)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+	}
+
+	l.mutex.Lock()
+	slots := l.slots
+	l.mutex.Unlock()
+	if slots == nil {
+		return func() {}, nil
+	}
+	select {
+	case slots <- struct{}{}:
+		return func() { <-slots }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// pause stops every request to the host for the given duration.
+func (l *limiter) pause(d time.Duration) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	if until := time.Now().Add(d); until.After(l.until) {
+		l.until = until
+	}
+}
+
+// Pause stops every request to the host for the given duration, for sites that tell
+// through their own headers how long to wait.
+func Pause(host string, d time.Duration) {
+	limit(host).pause(d)
+}
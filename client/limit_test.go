@@ -0,0 +1,81 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLimiterBurst(t *testing.T) {
+	l := newLimiter(Policy{Rate: 2, Burst: 3})
+
+	for range 3 {
+		assert.Zero(t, l.reserve())
+	}
+	// the bucket is empty, the next token comes after half a second at 2 per second
+	d := l.reserve()
+	assert.Greater(t, d, 400*time.Millisecond)
+	assert.LessOrEqual(t, d, 500*time.Millisecond)
+}
+
+func TestLimiterUnlimited(t *testing.T) {
+	l := newLimiter(Policy{})
+	for range 100 {
+		assert.Zero(t, l.reserve())
+	}
+}
+
+func TestLimiterPause(t *testing.T) {
+	l := newLimiter(Policy{})
+	l.pause(time.Minute)
+	l.pause(time.Second)
+
+	d := l.reserve()
+	assert.Greater(t, d, 50*time.Second, "a shorter pause does not cut a longer one")
+}
+
+func TestLimiterConcurrency(t *testing.T) {
+	l := newLimiter(Policy{Concurrency: 1})
+
+	release, err := l.wait(context.Background())
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = l.wait(ctx)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	release()
+	release, err = l.wait(context.Background())
+	require.NoError(t, err)
+	release()
+}
+
+func TestLimiterApply(t *testing.T) {
+	l := newLimiter(Policy{Rate: 1, Burst: 5, Concurrency: 2})
+	l.apply(Policy{Rate: 1, Burst: 1})
+
+	assert.Nil(t, l.slots)
+	assert.Zero(t, l.reserve())
+	assert.Positive(t, l.reserve(), "the tokens are capped to the new burst")
+}
+
+func TestPolicy(t *testing.T) {
+	lmutex.Lock()
+	saved := policies
+	policies = map[string]Policy{"example.com": {Rate: 1, Burst: 1, Concurrency: 1}}
+	lmutex.Unlock()
+	t.Cleanup(func() {
+		lmutex.Lock()
+		policies = saved
+		lmutex.Unlock()
+	})
+
+	assert.Equal(t, Policy{Rate: 1, Burst: 1, Concurrency: 1}, policy("api.example.com"))
+	assert.Equal(t, Policy{Rate: 1, Burst: 1, Concurrency: 1}, policy("example.com"))
+	assert.Equal(t, defaultPolicy, policy("example.org"))
+	assert.Equal(t, defaultPolicy, policy("com"))
+}
@@ -0,0 +1,264 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Rotation decides how the pool hands out proxies.
+type Rotation int
+
+const (
+	// RotateRequest uses the next healthy proxy for every request.
+	RotateRequest Rotation = iota
+	// RotateHost sticks to one proxy per host until it gets ejected.
+	RotateHost
+)
+
+const (
+	minCooldown = 30 * time.Second
+	maxCooldown = 30 * time.Minute
+)
+
+var pool *Pool
+
+// ErrNoProxy is returned when every proxy of the pool is ejected and none comes back in time.
+var ErrNoProxy = errors.New("every proxy is ejected")
+
+// SetPool makes every request with Args.Proxy go through the pool.
+func SetPool(p *Pool) {
+	pool = p
+}
+
+type node struct {
+	link   *url.URL
+	client *http.Client
+	tor    bool
+	fails  int
+	until  time.Time
+}
+
+// Pool holds several HTTP or SOCKS5 proxies, rotates them and ejects the ones that
+// get blocked or fail to connect. Ejected proxies come back after a cooldown that
+// grows with every failure, or sooner when Check finds them healthy.
+type Pool struct {
+	nodes    []*node
+	hosts    map[string]*node
+	idx      int
+	rotation Rotation
+	tor      *Tor
+	renewing bool
+	mutex    sync.Mutex
+}
+
+// NewPool creates a pool from proxy urls such as http://host:8118 or socks5://127.0.0.1:9050.
+func NewPool(rotation Rotation, links ...string) (*Pool, error) {
+	p := &Pool{
+		hosts:    make(map[string]*node),
+		rotation: rotation,
+	}
+
+	for _, v := range links {
+		v = strings.TrimSpace(v)
+		if v == "" {
+			continue
+		}
+
+		link, err := url.Parse(v)
+		if err != nil {
+			return nil, err
+		}
+		switch link.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return nil, errors.New("unsupported proxy scheme: " + link.Scheme)
+		}
+
+		transport := http.DefaultTransport.(*http.Transport).Clone()
+		transport.Proxy = http.ProxyURL(link)
+		p.nodes = append(p.nodes, &node{
+			link:   link,
+			client: &http.Client{Transport: transport, Timeout: 2 * time.Minute},
+		})
+	}
+	if len(p.nodes) == 0 {
+		return nil, errors.New("no proxy was given")
+	}
+
+	return p, nil
+}
+
+// SetTor lets the pool renew the Tor circuit instead of ejecting proxies that run on the same
+// host as the Tor control port.
+func (p *Pool) SetTor(t *Tor) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	p.tor = t
+	if t == nil {
+		return
+	}
+	host, _, _ := net.SplitHostPort(t.Addr)
+	for _, v := range p.nodes {
+		v.tor = strings.HasPrefix(v.link.Scheme, "socks5") && v.link.Hostname() == host
+	}
+}
+
+// Len returns the number of proxies in the pool.
+func (p *Pool) Len() int {
+	return len(p.nodes)
+}
+
+// next returns a healthy proxy for the host, or nil and the time the first ejected
+// proxy comes back.
+func (p *Pool) next(host string) (*node, time.Time) {
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+
+	now := time.Now()
+	if p.rotation == RotateHost {
+		if n, ok := p.hosts[host]; ok && now.After(n.until) {
+			return n, time.Time{}
+		}
+	}
+
+	var back time.Time
+	for range p.nodes {
+		n := p.nodes[p.idx]
+		p.idx = (p.idx + 1) % len(p.nodes)
+		if now.After(n.until) {
+			if p.rotation == RotateHost {
+				p.hosts[host] = n
+			}
+			return n, time.Time{}
+		}
+		if back.IsZero() || n.until.Before(back) {
+			back = n.until
+		}
+	}
+
+	return nil, back
+}
+
+// report records the outcome of a request made through the proxy. Challenge pages are
+// left out since the site blocks every client, not only the proxy.
+func (p *Pool) report(n *node, resp *http.Response, err error) {
+	if n == nil || (resp != nil && challenge(resp)) {
+		return
+	}
+
+	var status int
+	if resp != nil {
+		status = resp.StatusCode
+	}
+	if err == nil && status != http.StatusForbidden && status != http.StatusTooManyRequests {
+		p.mutex.Lock()
+		n.fails = 0
+		p.mutex.Unlock()
+		return
+	}
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	for k, v := range p.hosts {
+		if v == n {
+			delete(p.hosts, k)
+		}
+	}
+
+	// a Tor proxy only needs a new circuit, it waits for it in the background
+	if n.tor && p.tor != nil {
+		n.until = time.Now().Add(newnymDelay)
+		if !p.renewing {
+			p.renewing = true
+			go p.renew()
+		}
+		logger.Warn("tor proxy is waiting for a new circuit", "proxy", n.link.Host, "code", status, "error", err)
+		return
+	}
+
+	n.fails++
+	cooldown := min(minCooldown<<min(n.fails-1, 10), maxCooldown)
+	n.until = time.Now().Add(cooldown)
+	logger.Warn("proxy was ejected", "proxy", n.link.Host, "code", status, "cooldown", cooldown, "error", err)
+}
+
+// renew asks Tor for a new circuit and brings back the Tor proxies, or ejects them like the
+// other proxies when Tor cannot be reached.
+func (p *Pool) renew() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	err := p.tor.NewCircuit(ctx)
+
+	p.mutex.Lock()
+	defer p.mutex.Unlock()
+	p.renewing = false
+	for _, v := range p.nodes {
+		if !v.tor {
+			continue
+		}
+		if err == nil {
+			v.fails = 0
+			continue
+		}
+		v.fails++
+		v.until = time.Now().Add(min(minCooldown<<min(v.fails-1, 10), maxCooldown))
+	}
+	if err != nil {
+		logger.Error("cannot renew tor circuit", "control", p.tor.Addr, "error", err)
+	}
+}
+
+// Check sends a request through every ejected proxy and brings back the ones that answer.
+func (p *Pool) Check(ctx context.Context, target string) {
+	p.mutex.Lock()
+	var ejected []*node
+	for _, v := range p.nodes {
+		if time.Now().Before(v.until) {
+			ejected = append(ejected, v)
+		}
+	}
+	p.mutex.Unlock()
+
+	for _, v := range ejected {
+		req, err := http.NewRequestWithContext(ctx, http.MethodHead, target, nil)
+		if err != nil {
+			return
+		}
+		req.Header.Set("User-Agent", UserAgent)
+
+		resp, err := v.client.Do(req)
+		if err != nil {
+			continue
+		}
+		resp.Body.Close()
+		if resp.StatusCode >= http.StatusBadRequest {
+			continue
+		}
+
+		p.mutex.Lock()
+		v.until = time.Time{}
+		p.mutex.Unlock()
+		logger.Info("proxy is healthy again", "proxy", v.link.Host)
+	}
+}
+
+// Watch runs Check on every tick until the context is done.
+func (p *Pool) Watch(ctx context.Context, target string, every time.Duration) {
+	ticker := time.NewTicker(every)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			p.Check(ctx, target)
+		}
+	}
+}
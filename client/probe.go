@@ -0,0 +1,80 @@
+package client
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/url"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+// Reply is the answer of a Probe, Link is where the redirects ended.
+type Reply struct {
+	Status int
+	Header http.Header
+	Body   []byte
+	Link   *url.URL
+}
+
+// Probe sends the request once, without retries nor the cache, and returns whatever the site
+// answered, error codes included. At most Args.Limit bytes of the body are kept. It goes through
+// the host limiter and the proxy pool like Do, and is meant for the callers that need the status
+// and the headers, such as the link checks or the APIs that send their own rate limits.
+func Probe(ctx context.Context, args *Args) (*Reply, error) {
+	if args.Endpoint == nil {
+		return nil, errs.ErrBadData
+	}
+
+	body, err := payload(args.Body)
+	if err != nil {
+		return nil, errs.ErrBadData
+	}
+
+	client, through, err := agent(ctx, args)
+	if err != nil {
+		return nil, err
+	}
+	if recorder != nil {
+		client = recorder.client(client)
+	}
+	req, err := request(ctx, args, body)
+	if err != nil {
+		return nil, err
+	}
+
+	release, err := limit(args.Endpoint.Host).wait(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	resp, err := client.Do(req)
+	if err != nil {
+		err = redact(err)
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if through != nil {
+			pool.report(through, nil, err)
+		}
+		return nil, err
+	}
+	defer discard(resp)
+	if through != nil {
+		pool.report(through, resp, nil)
+	}
+
+	data, err := io.ReadAll(io.LimitReader(resp.Body, args.limit()))
+	if err != nil && ctx.Err() != nil {
+		return nil, ctx.Err()
+	}
+
+	logger.Info("probed link", "code", resp.StatusCode, "host", args.Endpoint.Host, "link", args.Endpoint.Path)
+	return &Reply{
+		Status: resp.StatusCode,
+		Header: resp.Header,
+		Body:   data,
+		Link:   resp.Request.URL,
+	}, nil
+}
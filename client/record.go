@@ -0,0 +1,279 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+)
+
+// Mode tells the recorder whether it talks to the network.
+type Mode int
+
+const (
+	// Replay answers every request from the cassette and never touches the network.
+	Replay Mode = iota
+	// Record sends the requests and appends every exchange to the cassette.
+	Record
+)
+
+// ErrNoInteraction is returned in replay mode for a request missing from the cassette.
+var ErrNoInteraction = errors.New("no recorded interaction")
+
+var recorder *Recorder
+
+// SetRecorder routes every request of Do and Stream through the recorder, nil turns it off.
+func SetRecorder(r *Recorder) {
+	recorder = r
+}
+
+type interaction struct {
+	Method   string      `json:"Method"`
+	Link     string      `json:"Link"`
+	Body     string      `json:"Body,omitempty"`
+	Status   int         `json:"Status"`
+	Header   http.Header `json:"Header,omitempty"`
+	Response string      `json:"Response"`
+	used     bool
+}
+
+// Recorder is a cassette of request and response pairs stored as JSON on disk.
+// Requests are matched by method, url and body, in the order they were recorded.
+type Recorder struct {
+	path   string
+	mode   Mode
+	redact []string
+	tapes  []*interaction
+	mutex  sync.Mutex
+}
+
+// NewRecorder opens the cassette at the path. The query parameters and the fields of JSON
+// request bodies named in redact, such as api keys, are neither stored nor used to match requests.
+func NewRecorder(path string, mode Mode, redact ...string) (*Recorder, error) {
+	r := &Recorder{
+		path:   path,
+		mode:   mode,
+		redact: redact,
+	}
+	if mode == Record {
+		return r, nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &r.tapes); err != nil {
+		return nil, err
+	}
+
+	return r, nil
+}
+
+// Save writes the recorded interactions to the cassette.
+func (r *Recorder) Save() error {
+	if r.mode != Record {
+		return nil
+	}
+
+	r.mutex.Lock()
+	data, err := json.MarshalIndent(r.tapes, "", "  ")
+	r.mutex.Unlock()
+	if err != nil {
+		return err
+	}
+	if err = os.MkdirAll(filepath.Dir(r.path), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(r.path, data, 0o644)
+}
+
+// Unused returns the links of the interactions that no request asked for.
+func (r *Recorder) Unused() []string {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var links []string
+	for _, v := range r.tapes {
+		if !v.used {
+			links = append(links, v.Method+" "+v.Link)
+		}
+	}
+	return links
+}
+
+// clean returns the link without the redacted query parameters.
+func (r *Recorder) clean(link *url.URL) string {
+	if len(r.redact) == 0 || link.RawQuery == "" {
+		return link.String()
+	}
+
+	u := *link
+	query := u.Query()
+	for k := range query {
+		if r.secret(k) {
+			query.Del(k)
+		}
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// secret reports whether the query parameter or the body field is redacted, names are
+// compared without case like errs.Redact does.
+func (r *Recorder) secret(name string) bool {
+	return slices.ContainsFunc(r.redact, func(v string) bool {
+		return strings.EqualFold(v, name)
+	})
+}
+
+// strip returns the request body without the redacted fields when it is a JSON object.
+func (r *Recorder) strip(body []byte) string {
+	if len(r.redact) == 0 || !bytes.HasPrefix(bytes.TrimSpace(body), []byte("{")) {
+		return string(body)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(body, &fields); err != nil {
+		return string(body)
+	}
+	var found bool
+	for k := range fields {
+		if r.secret(k) {
+			delete(fields, k)
+			found = true
+		}
+	}
+	if !found {
+		return string(body)
+	}
+
+	data, err := json.Marshal(fields)
+	if err != nil {
+		return string(body)
+	}
+	return string(data)
+}
+
+// conceal keeps the cookies the site sets in the cassette but hides their values, they are
+// often session or clearance tokens and replaying them only needs their names and attributes.
+func conceal(header http.Header) {
+	lines := header.Values("Set-Cookie")
+	if len(lines) == 0 {
+		return
+	}
+
+	header.Del("Set-Cookie")
+	for _, v := range lines {
+		pair, attrs, _ := strings.Cut(v, ";")
+		name, _, _ := strings.Cut(pair, "=")
+		line := strings.TrimSpace(name) + "=redacted"
+		if attrs != "" {
+			line += ";" + attrs
+		}
+		header.Add("Set-Cookie", line)
+	}
+}
+
+// client wraps the http client so its requests go through the recorder.
+func (r *Recorder) client(c *http.Client) *http.Client {
+	next := c.Transport
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &http.Client{
+		Transport:     &tape{recorder: r, next: next},
+		CheckRedirect: c.CheckRedirect,
+		Timeout:       c.Timeout,
+	}
+}
+
+type tape struct {
+	recorder *Recorder
+	next     http.RoundTripper
+}
+
+func (t *tape) RoundTrip(req *http.Request) (*http.Response, error) {
+	var body []byte
+	if req.Body != nil {
+		data, err := io.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		body = data
+		req.Body = io.NopCloser(bytes.NewReader(data))
+	}
+
+	r := t.recorder
+	link := r.clean(req.URL)
+	if r.mode == Replay {
+		return r.replay(req, link, r.strip(body))
+	}
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	header := resp.Header.Clone()
+	conceal(header)
+	r.mutex.Lock()
+	r.tapes = append(r.tapes, &interaction{
+		Method:   req.Method,
+		Link:     link,
+		Body:     r.strip(body),
+		Status:   resp.StatusCode,
+		Header:   header,
+		Response: string(data),
+	})
+	r.mutex.Unlock()
+
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	return resp, nil
+}
+
+func (r *Recorder) replay(req *http.Request, link, body string) (*http.Response, error) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	var found *interaction
+	for _, v := range r.tapes {
+		if v.Method != req.Method || v.Link != link || v.Body != body {
+			continue
+		}
+		found = v
+		if !v.used {
+			break
+		}
+	}
+	if found == nil {
+		return nil, fmt.Errorf("%w: %s %s", ErrNoInteraction, req.Method, link)
+	}
+	found.used = true
+
+	return &http.Response{
+		Status:        http.StatusText(found.Status),
+		StatusCode:    found.Status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        found.Header.Clone(),
+		Body:          io.NopCloser(bytes.NewReader([]byte(found.Response))),
+		ContentLength: int64(len(found.Response)),
+		Request:       req,
+	}, nil
+}
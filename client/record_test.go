@@ -0,0 +1,37 @@
+package client
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRecorderRedact(t *testing.T) {
+	r := &Recorder{redact: errs.Secrets}
+
+	link, _ := url.Parse("https://api.example.com/v1/search?q=frieren&Access_Token=secret&client_id=abc&page=2")
+	assert.Equal(t, "https://api.example.com/v1/search?page=2&q=frieren", r.clean(link))
+
+	link, _ = url.Parse("https://api.example.com/v1/search?q=frieren")
+	assert.Equal(t, "https://api.example.com/v1/search?q=frieren", r.clean(link))
+
+	assert.JSONEq(t, `{"pin":"1234"}`, r.strip([]byte(`{"apikey":"secret","client_secret":"x","pin":"1234"}`)))
+	assert.Equal(t, `query=frieren`, r.strip([]byte(`query=frieren`)))
+}
+
+func TestConceal(t *testing.T) {
+	header := http.Header{}
+	header.Add("Set-Cookie", "cf_clearance=abc123; Path=/; Max-Age=7200; HttpOnly")
+	header.Add("Set-Cookie", "session=xyz")
+	header.Set("Content-Type", "text/html")
+
+	conceal(header)
+	assert.Equal(t, []string{
+		"cf_clearance=redacted; Path=/; Max-Age=7200; HttpOnly",
+		"session=redacted",
+	}, header.Values("Set-Cookie"))
+	assert.Equal(t, "text/html", header.Get("Content-Type"))
+}
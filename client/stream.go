@@ -0,0 +1,42 @@
+package client
+
+import (
+	"errors"
+	"io"
+	"sync"
+)
+
+// ErrTooLarge is returned when a response body goes past Args.Limit.
+var ErrTooLarge = errors.New("response body too large")
+
+// stream is a response body with a size cap that frees its host slot once closed.
+type stream struct {
+	body    io.ReadCloser
+	left    int64
+	release func()
+	once    sync.Once
+}
+
+func (x *stream) Read(p []byte) (int, error) {
+	if x.left <= 0 {
+		// read one more byte to tell a body that ends right at the cap from a bigger one
+		n, err := x.body.Read(make([]byte, 1))
+		if n > 0 {
+			return 0, ErrTooLarge
+		}
+		return 0, err
+	}
+	if int64(len(p)) > x.left {
+		p = p[:x.left]
+	}
+
+	n, err := x.body.Read(p)
+	x.left -= int64(n)
+	return n, err
+}
+
+func (x *stream) Close() error {
+	err := x.body.Close()
+	x.once.Do(x.release)
+	return err
+}
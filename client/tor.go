@@ -0,0 +1,131 @@
+package client
+
+import (
+	"bufio"
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Tor talks to the Tor control port to request new circuits.
+type Tor struct {
+	// Addr is the control port address, for example 127.0.0.1:9051.
+	Addr string
+	// Password is used for HashedControlPassword authentication.
+	Password string
+	// CookieFile is used for CookieAuthentication, it is ignored when a password is set.
+	CookieFile string
+
+	mutex sync.Mutex
+	last  time.Time
+}
+
+// Tor refuses NEWNYM signals sent faster than this.
+const newnymDelay = 10 * time.Second
+
+// NewCircuit sends the NEWNYM signal so new connections use a fresh exit node. Tor ignores
+// the signals sent faster than newnymDelay, so it waits for the delay to pass.
+func (t *Tor) NewCircuit(ctx context.Context) error {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if d := newnymDelay - time.Since(t.last); d > 0 {
+		if err := sleep(ctx, d); err != nil {
+			return err
+		}
+	}
+
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "tcp", t.Addr)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	} else {
+		conn.SetDeadline(time.Now().Add(30 * time.Second))
+	}
+
+	auth, err := t.auth()
+	if err != nil {
+		return err
+	}
+
+	rw := bufio.NewReadWriter(bufio.NewReader(conn), bufio.NewWriter(conn))
+	for _, cmd := range []string{"AUTHENTICATE " + auth, "SIGNAL NEWNYM"} {
+		if err = command(rw, cmd); err != nil {
+			return err
+		}
+	}
+	command(rw, "QUIT")
+
+	t.last = time.Now()
+	logger.Info("tor circuit was renewed", "control", t.Addr)
+
+	return nil
+}
+
+// Rotate renews the circuit on every tick until the context is done.
+func (t *Tor) Rotate(ctx context.Context, every time.Duration) {
+	ticker := time.NewTicker(max(every, newnymDelay))
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := t.NewCircuit(ctx); err != nil {
+				logger.Error("cannot renew tor circuit", "control", t.Addr, "error", err)
+			}
+		}
+	}
+}
+
+func (t *Tor) auth() (string, error) {
+	if t.Password != "" {
+		return strconv.Quote(t.Password), nil
+	}
+	if t.CookieFile != "" {
+		cookie, err := os.ReadFile(t.CookieFile)
+		if err != nil {
+			return "", err
+		}
+		return hex.EncodeToString(cookie), nil
+	}
+	return `""`, nil
+}
+
+// command writes a control command and checks that the reply is a success.
+func command(rw *bufio.ReadWriter, cmd string) error {
+	if _, err := rw.WriteString(cmd + "\r\n"); err != nil {
+		return err
+	}
+	if err := rw.Flush(); err != nil {
+		return err
+	}
+
+	for {
+		line, err := rw.ReadString('\n')
+		if err != nil {
+			return err
+		}
+		line = strings.TrimSpace(line)
+		if len(line) < 4 {
+			return errors.New("invalid tor reply: " + line)
+		}
+		if !strings.HasPrefix(line, "250") {
+			return fmt.Errorf("tor refused %q: %s", strings.Fields(cmd)[0], line)
+		}
+		// "250-" and "250+" announce more lines, "250 " ends the reply.
+		if line[3] == ' ' {
+			return nil
+		}
+	}
+}
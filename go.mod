@@ -7,6 +7,7 @@ require (
 	github.com/cyruzin/golang-tmdb v1.6.7
 	github.com/djeddi-yacine/jikan-go v0.0.0-20240921195335-eb2ccfadc399
 	github.com/stretchr/testify v1.9.0
+	go.etcd.io/bbolt v1.3.11
 	golang.org/x/sync v0.8.0
 )
 
@@ -18,5 +19,6 @@ require (
 	github.com/modern-go/reflect2 v1.0.2 // indirect
 	github.com/pmezard/go-difflib v1.0.0 // indirect
 	golang.org/x/net v0.29.0 // indirect
+	golang.org/x/sys v0.25.0 // indirect
 	gopkg.in/yaml.v3 v3.0.1 // indirect
 )
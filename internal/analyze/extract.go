@@ -2,8 +2,10 @@ package analyze
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/anicine/anicine-scraper/internal/errs"
 	"github.com/anicine/anicine-scraper/internal/shared"
@@ -29,6 +31,30 @@ func ExtractYear(input string) (int, error) {
 	return int(year), nil
 }
 
+// ExtractAnimeDate parses a date written as YYYY-MM-DD, YYYY-MM or YYYY, the parts that
+// are left out stay zero. An empty input gives the zero date.
+func ExtractAnimeDate(input string) (models.AnimeDate, error) {
+	if input == "" {
+		return models.AnimeDate{}, nil
+	}
+
+	for _, layout := range []string{time.DateOnly, "2006-01", "2006"} {
+		if t, err := time.Parse(layout, input); err == nil {
+			var data models.AnimeDate
+			data.Year = t.Year()
+			if len(layout) > 4 {
+				data.Month = int(t.Month())
+			}
+			if len(layout) > 7 {
+				data.Day = t.Day()
+			}
+			return data, nil
+		}
+	}
+
+	return models.AnimeDate{}, fmt.Errorf("%w: invalid date %q", errs.ErrBadData, input)
+}
+
 func ExtractAid(input string) (int, error) {
 	if input == "" {
 		return 0, errs.ErrBadData
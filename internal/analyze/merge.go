@@ -668,7 +668,9 @@ func MergeAnimeContentRating(anime ...*models.Anime) string {
 		}
 	}
 
-	result = *CleanRepetition(&filter)
+	if r := CleanRepetition(&filter); r != nil {
+		result = *r
+	}
 
 	return result
 }
@@ -848,6 +850,69 @@ func MergeAnimeTypes(anime ...*models.Anime) string {
 	return strings.ToUpper(data)
 }
 
+// MergeAnime merges every field of the given anime into a single one.
+func MergeAnime(anime ...*models.Anime) *models.Anime {
+	merged := &models.Anime{
+		Type:            MergeAnimeTypes(anime...),
+		Resources:       MergeAnimeResource(anime...),
+		Titles:          MergeAnimeTitle(anime...),
+		Description:     MergeAnimeOverview(anime...),
+		PortraitIMG:     MergeAnimePortraitIMG(anime...),
+		LandscapeIMG:    MergeAnimeLandscapeIMG(anime...),
+		ContentRating:   MergeAnimeContentRating(anime...),
+		CountryOfOrigin: MergeAnimeCountry(anime...),
+		Studios:         MergeAnimeStudios(anime...),
+		Genres:          MergeAnimeGenres(anime...),
+		Producers:       MergeAnimeProducers(anime...),
+		Licensors:       MergeAnimeLicensors(anime...),
+		Tags:            MergeAnimeTags(anime...),
+		Relations:       MergeAnimeRelation(anime...),
+		Characters:      MergeAnimeCharacter(anime...),
+		Trailers:        MergeAnimeTrailer(anime...),
+		External:        MergeAnimeExternals(anime...),
+		Posters:         MergeAnimePosters(anime...),
+		Backdrops:       MergeAnimeBackdrops(anime...),
+		Logos:           MergeAnimeLogos(anime...),
+		Banners:         MergeAnimeBanners(anime...),
+		Arts:            MergeAnimeArts(anime...),
+	}
+
+	for _, v := range anime {
+		if v == nil {
+			continue
+		}
+		if merged.Status == "" {
+			merged.Status = v.Status
+		}
+		if merged.StartAt.IsZero() {
+			merged.StartAt = v.StartAt
+		}
+		if merged.EndAt.IsZero() {
+			merged.EndAt = v.EndAt
+		}
+		if merged.Period.Season == "" {
+			merged.Period = v.Period
+		}
+		if len(merged.MetaData) == 0 {
+			merged.MetaData = v.MetaData
+		}
+		if len(merged.InnerSeasons) == 0 {
+			merged.InnerSeasons = v.InnerSeasons
+		}
+		if len(merged.Episodes) == 0 {
+			merged.Episodes = v.Episodes
+		}
+		if len(merged.Themes.OP) == 0 && len(merged.Themes.ED) == 0 {
+			merged.Themes = v.Themes
+		}
+	}
+	if merged.Period.Season == "" {
+		merged.Period = ExtractAnimePeriod(merged.StartAt)
+	}
+
+	return merged
+}
+
 // func mergeAnimeStartDate(anime ...*models.Anime) models.AnimeDate {
 // 	var (
 // 		filter = make(map[models.AnimeDate]bool)
@@ -0,0 +1,277 @@
+package analyze
+
+import (
+	"cmp"
+	"net/url"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/models"
+)
+
+var heightRgx = regexp.MustCompile(`(\d{3,4})\s*p`)
+
+// CleanQuality maps the labels the sites give to their servers, such as "FHD", "HD 720p" or "SD", to a quality.
+func CleanQuality(input string) models.Quality {
+	input = strings.ToLower(strings.TrimSpace(input))
+	if input == "" {
+		return models.QualityUnknown
+	}
+
+	if match := heightRgx.FindStringSubmatch(input); match != nil {
+		n, _ := strconv.Atoi(match[1])
+		return CleanHeight(n)
+	}
+	switch {
+	case strings.Contains(input, "4k"), strings.Contains(input, "uhd"), strings.Contains(input, "2160"):
+		return models.Quality2160
+	case strings.Contains(input, "fhd"), strings.Contains(input, "full"), strings.Contains(input, "1080"):
+		return models.Quality1080
+	case strings.Contains(input, "hd"), strings.Contains(input, "720"):
+		return models.Quality720
+	case strings.Contains(input, "sd"), strings.Contains(input, "480"):
+		return models.Quality480
+	case strings.Contains(input, "ld"), strings.Contains(input, "low"), strings.Contains(input, "360"):
+		return models.Quality360
+	}
+
+	return models.QualityUnknown
+}
+
+// CleanHeight maps a number of lines to the closest quality at or below it.
+func CleanHeight(height int) models.Quality {
+	switch {
+	case height >= 2160:
+		return models.Quality2160
+	case height >= 1080:
+		return models.Quality1080
+	case height >= 720:
+		return models.Quality720
+	case height >= 480:
+		return models.Quality480
+	case height > 0:
+		return models.Quality360
+	}
+	return models.QualityUnknown
+}
+
+// CleanTrack maps the labels the sites give to their versions, such as "Dub", "Sub ITA" or "RAW", to a track.
+func CleanTrack(input string) models.Track {
+	input = strings.ToLower(input)
+	switch {
+	case strings.Contains(input, "softsub"), strings.Contains(input, "soft sub"):
+		return models.TrackSoftSub
+	case strings.Contains(input, "dub"):
+		return models.TrackDub
+	case strings.Contains(input, "raw"):
+		return models.TrackRaw
+	}
+	return models.TrackSub
+}
+
+// CleanAudio returns the audio language of a video of the track from a site in the language.
+func CleanAudio(track models.Track, language string) models.Language {
+	if track == models.TrackDub {
+		return CleanLanguage(language)
+	}
+	return CleanLanguage("japanese")
+}
+
+// CleanSubtitle returns the subtitle language of a video of the track from a site in the language.
+func CleanSubtitle(track models.Track, language string) models.Language {
+	if track == models.TrackSub || track == models.TrackSoftSub {
+		return CleanLanguage(language)
+	}
+	return models.Language{}
+}
+
+// VideoRank is what MergeAnimeVideos knows about the videos beyond their own fields.
+type VideoRank struct {
+	// Languages are the preferred ISO 639-1 codes, the first one is the best. Subtitled videos
+	// match on their subtitle language and dubbed ones on their audio.
+	Languages []string
+	// Hosts is the share of links of each host, as returned by ExtractSourceHost, found alive in the past.
+	Hosts map[string]float64
+	// Alive holds the result of the link checks by ExtractSourceKey, true for alive and false for dead.
+	Alive map[string]bool
+}
+
+// CleanSource trims an embed url and gives a scheme to the protocol relative ones, the url is
+// otherwise kept as the provider gave it, see ExtractSourceKey to compare two sources.
+func CleanSource(source string) string {
+	source = strings.TrimSpace(source)
+	if strings.HasPrefix(source, "//") {
+		source = "https:" + source
+	}
+	return source
+}
+
+// mirrors maps the domain labels of the embed hosts that serve the same files under several
+// domains to the name of the host, other hosts are never folded.
+var mirrors = map[string]string{
+	"dood": "dood", "doodstream": "dood", "doods": "dood", "dooood": "dood", "d000d": "dood",
+	"d0000d": "dood", "ds2play": "dood", "ds2video": "dood",
+	"mixdrop": "mixdrop", "mixdrp": "mixdrop", "m1xdrop": "mixdrop",
+	"streamtape": "streamtape", "strtape": "streamtape", "streamta": "streamtape", "strcloud": "streamtape",
+	"streamwish": "streamwish", "embedwish": "streamwish", "wishfast": "streamwish", "awish": "streamwish",
+	"dwish": "streamwish", "swdyu": "streamwish",
+	"filelions": "filelions", "alions": "filelions",
+	"vidhide": "vidhide", "vidhidepro": "vidhide", "vidhidevip": "vidhide", "streamhide": "vidhide",
+	"filemoon": "filemoon", "vidmoly": "vidmoly", "uqload": "uqload",
+}
+
+// ExtractSourceHost returns the name of the host of the source. The known mirror domains share
+// one name, such as dood for dood.to and doodstream.com, any other host keeps its domain without
+// the www, so unrelated sites sharing a label on another TLD stay apart.
+func ExtractSourceHost(source string) string {
+	if strings.HasPrefix(source, "//") {
+		source = "https:" + source
+	}
+	link, err := url.Parse(source)
+	if err != nil {
+		return ""
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(link.Hostname()), "www.")
+	labels := strings.Split(host, ".")
+	if len(labels) < 2 {
+		return host
+	}
+	if name, ok := mirrors[labels[len(labels)-2]]; ok {
+		return name
+	}
+	return host
+}
+
+// ExtractSourceKey returns the key two sources of the same file share: the scheme, the www, the
+// domain of the known mirrors, the fragment, the tracking parameters and the trailing slash are
+// left out, and the download page of the hosts that have one is read as their embed page. The
+// key is only meant for comparing, the sources themselves are never rewritten.
+func ExtractSourceKey(source string) string {
+	source = CleanSource(source)
+	link, err := url.Parse(source)
+	if err != nil || link.Host == "" {
+		return source
+	}
+
+	host := ExtractSourceHost(source)
+	path := link.Path
+	switch host {
+	case "dood":
+		path = strings.Replace(path, "/d/", "/e/", 1)
+	case "mixdrop":
+		path = strings.Replace(path, "/f/", "/e/", 1)
+	case "streamtape":
+		path = strings.Replace(path, "/v/", "/e/", 1)
+	}
+
+	key := host + strings.TrimSuffix(path, "/")
+	if link.RawQuery != "" {
+		query := link.Query()
+		for k := range query {
+			if strings.HasPrefix(strings.ToLower(k), "utm_") {
+				query.Del(k)
+			}
+		}
+		if len(query) > 0 {
+			key += "?" + query.Encode()
+		}
+	}
+	return key
+}
+
+// tier sorts alive links first, unchecked ones next and dead ones last.
+func (x *VideoRank) tier(video models.AnimeVideo) int {
+	alive, ok := x.Alive[ExtractSourceKey(video.Source)]
+	switch {
+	case !ok:
+		return 1
+	case alive:
+		return 0
+	}
+	return 2
+}
+
+// language returns the position of the language of the video in the preferences.
+func (x *VideoRank) language(video models.AnimeVideo) int {
+	code := video.Subtitle.ISO639_1
+	if video.Type == models.TrackDub || code == "" {
+		code = video.Audio.ISO639_1
+	}
+	if i := slices.Index(x.Languages, code); i >= 0 {
+		return i
+	}
+	return len(x.Languages)
+}
+
+func (x *VideoRank) reliability(video models.AnimeVideo) float64 {
+	if v, ok := x.Hosts[ExtractSourceHost(video.Source)]; ok {
+		return v
+	}
+	return 0.5
+}
+
+// compare orders the videos from the best: alive, preferred language, quality then host reliability.
+func (x *VideoRank) compare(a, b models.AnimeVideo) int {
+	if c := cmp.Compare(x.tier(a), x.tier(b)); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(x.language(a), x.language(b)); c != 0 {
+		return c
+	}
+	if c := cmp.Compare(b.Quality.Height(), a.Quality.Height()); c != 0 {
+		return c
+	}
+	return cmp.Compare(x.reliability(b), x.reliability(a))
+}
+
+// MergeAnimeVideos drops the videos pointing to the same file, keeping the best ranked copy
+// filled with what the others know, and returns them from the best to the worst.
+func MergeAnimeVideos(rank *VideoRank, videos ...[]models.AnimeVideo) []models.AnimeVideo {
+	if rank == nil {
+		rank = new(VideoRank)
+	}
+
+	var (
+		data  []models.AnimeVideo
+		index = make(map[string]int)
+	)
+	for _, list := range videos {
+		for _, v := range list {
+			if strings.TrimSpace(v.Source) == "" {
+				continue
+			}
+
+			key := ExtractSourceKey(v.Source)
+			i, found := index[key]
+			if !found {
+				index[key] = len(data)
+				data = append(data, v)
+				continue
+			}
+
+			kept := data[i]
+			if rank.compare(v, kept) < 0 {
+				kept, v = v, kept
+			}
+			if kept.Referer == "" {
+				kept.Referer = v.Referer
+			}
+			if kept.Manifest == nil {
+				kept.Manifest = v.Manifest
+			}
+			if len(kept.Streams) == 0 {
+				kept.Streams, kept.Subtitles = v.Streams, v.Subtitles
+			}
+			if kept.Quality.Height() == 0 {
+				kept.Quality = v.Quality
+			}
+			data[i] = kept
+		}
+	}
+
+	slices.SortStableFunc(data, rank.compare)
+	return data
+}
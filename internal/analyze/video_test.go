@@ -0,0 +1,145 @@
+package analyze
+
+import (
+	"testing"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExtractSourceHost(t *testing.T) {
+	for source, want := range map[string]string{
+		"https://dood.wf/e/x8kq2m1v9z0a":           "dood",
+		"https://doodstream.com/e/x8kq2m1v9z0a":    "dood",
+		"//d0000d.com/e/x8kq2m1v9z0a":              "dood",
+		"https://mixdrp.to/e/3nv8rq4ktw7pxl":       "mixdrop",
+		"https://streamta.pe/e/Vb1yZ0kWqpcO6bR":    "streamtape",
+		"https://awish.pro/e/k2fq9wz1l8mr":         "streamwish",
+		"https://www.mp4upload.com/embed-abc.html": "mp4upload.com",
+		"https://ok.ru/videoembed/6183847218915":   "ok.ru",
+		"https://vk.com/video_ext.php?oid=-1&id=2": "vk.com",
+		"https://vk.ru/video_ext.php?oid=-1&id=2":  "vk.ru",
+		"not a url %%": "",
+	} {
+		assert.Equal(t, want, ExtractSourceHost(source), source)
+	}
+}
+
+func TestExtractSourceKey(t *testing.T) {
+	same := [][]string{
+		{
+			"https://dood.wf/e/x8kq2m1v9z0a",
+			"https://dood.to/e/x8kq2m1v9z0a/",
+			"http://www.doodstream.com/d/x8kq2m1v9z0a",
+			"//dood.watch/e/x8kq2m1v9z0a#player",
+		},
+		{
+			"https://streamtape.com/e/Vb1yZ0kWqpcO6bR/Frieren_01.mp4",
+			"https://streamtape.to/v/Vb1yZ0kWqpcO6bR/Frieren_01.mp4",
+		},
+		{
+			"https://mixdrop.co/e/3nv8rq4ktw7pxl",
+			"https://mixdrop.ag/f/3nv8rq4ktw7pxl?utm_source=witanime",
+		},
+		{
+			"https://www.mp4upload.com/embed-m0fx1tz4q2lk.html",
+			"https://mp4upload.com/embed-m0fx1tz4q2lk.html",
+		},
+	}
+	for _, v := range same {
+		for _, source := range v[1:] {
+			assert.Equal(t, ExtractSourceKey(v[0]), ExtractSourceKey(source), source)
+		}
+	}
+
+	different := [][2]string{
+		{"https://dood.wf/e/x8kq2m1v9z0a", "https://dood.wf/e/a0z9v1m2qk8x"},
+		{"https://vk.com/video_ext.php?oid=-1&id=2", "https://vk.ru/video_ext.php?oid=-1&id=2"},
+		{"https://video.example.com/e/abc", "https://video.example.org/e/abc"},
+		{"https://ok.ru/videoembed/6183847218915", "https://ok.ru/videoembed/6183847218916"},
+		{"https://filemoon.sx/e/k2fq9wz1l8mr", "https://streamwish.to/e/k2fq9wz1l8mr"},
+	}
+	for _, v := range different {
+		assert.NotEqual(t, ExtractSourceKey(v[0]), ExtractSourceKey(v[1]), v[1])
+	}
+}
+
+func video(source string, track models.Track, quality models.Quality, subtitle string) models.AnimeVideo {
+	return models.AnimeVideo{
+		Source:   source,
+		Type:     track,
+		Quality:  quality,
+		Audio:    models.Language{ISO639_1: "ja"},
+		Subtitle: models.Language{ISO639_1: subtitle},
+	}
+}
+
+func sources(videos []models.AnimeVideo) []string {
+	var data []string
+	for _, v := range videos {
+		data = append(data, v.Source)
+	}
+	return data
+}
+
+func TestMergeAnimeVideos(t *testing.T) {
+	gogo := []models.AnimeVideo{
+		video("https://dood.wf/e/x8kq2m1v9z0a", models.TrackSub, models.QualityUnknown, "en"),
+		video("https://streamwish.to/e/k2fq9wz1l8mr", models.TrackSub, models.Quality1080, "en"),
+	}
+	witanime := []models.AnimeVideo{
+		video("https://doodstream.com/d/x8kq2m1v9z0a", models.TrackSub, models.Quality720, "ar"),
+		video("https://www.mp4upload.com/embed-m0fx1tz4q2lk.html", models.TrackSub, models.Quality720, "ar"),
+		{Source: "  "},
+	}
+	witanime[0].Referer = "https://witanime.one/"
+
+	data := MergeAnimeVideos(&VideoRank{Languages: []string{"ar", "en"}}, gogo, witanime)
+	assert.Equal(t, []string{
+		"https://doodstream.com/d/x8kq2m1v9z0a",
+		"https://www.mp4upload.com/embed-m0fx1tz4q2lk.html",
+		"https://streamwish.to/e/k2fq9wz1l8mr",
+	}, sources(data), "the copy in the preferred language is kept")
+	assert.Equal(t, "https://witanime.one/", data[0].Referer)
+	assert.Equal(t, models.Quality720, data[0].Quality)
+
+	// the copy kept takes the quality the other one knows
+	data = MergeAnimeVideos(&VideoRank{Languages: []string{"en"}}, gogo, witanime[:1])
+	assert.Equal(t, "https://dood.wf/e/x8kq2m1v9z0a", data[1].Source)
+	assert.Equal(t, models.Quality720, data[1].Quality)
+	assert.Equal(t, "https://witanime.one/", data[1].Referer)
+}
+
+func TestVideoRank(t *testing.T) {
+	var (
+		dead     = video("https://dood.wf/e/x8kq2m1v9z0a", models.TrackSub, models.Quality1080, "en")
+		unknown  = video("https://ok.ru/videoembed/6183847218915", models.TrackSub, models.Quality480, "en")
+		alive    = video("https://streamwish.to/e/k2fq9wz1l8mr", models.TrackSub, models.Quality360, "fr")
+		flaky    = video("https://filemoon.sx/e/a0z9v1m2qk8x", models.TrackSub, models.Quality720, "en")
+		reliable = video("https://mixdrop.co/e/3nv8rq4ktw7pxl", models.TrackSub, models.Quality720, "en")
+		dubbed   = models.AnimeVideo{
+			Source:  "https://streamtape.com/e/Vb1yZ0kWqpcO6bR",
+			Type:    models.TrackDub,
+			Quality: models.Quality720,
+			Audio:   models.Language{ISO639_1: "en"},
+		}
+	)
+	rank := &VideoRank{
+		Languages: []string{"en"},
+		Hosts:     map[string]float64{"filemoon": 0.2, "mixdrop": 0.9},
+		Alive: map[string]bool{
+			ExtractSourceKey("https://doodstream.com/e/x8kq2m1v9z0a"): false,
+			ExtractSourceKey(alive.Source):                            true,
+		},
+	}
+
+	data := MergeAnimeVideos(rank, []models.AnimeVideo{dead, unknown, flaky, dubbed, reliable, alive})
+	assert.Equal(t, []string{
+		alive.Source,    // alive before unchecked, whatever the language
+		reliable.Source, // then the language and quality, then the host
+		dubbed.Source,   // dubbed in the preferred language, a 0.5 host
+		flaky.Source,
+		unknown.Source,
+		dead.Source, // dead on a mirror, last
+	}, sources(data))
+}
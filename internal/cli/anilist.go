@@ -0,0 +1,40 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/anilist"
+)
+
+func anilistCmd(ctx context.Context, args []string) error {
+	var (
+		fs  = flag.NewFlagSet("anilist", flag.ContinueOnError)
+		id  = fs.Int("id", 0, "AniList id of the anime")
+		mal = fs.Int("mal", 0, "MyAnimeList id of the anime")
+		out = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		anime *models.Anime
+		err   error
+	)
+	switch {
+	case *id != 0:
+		anime, err = anilist.Anime(ctx, *id)
+	case *mal != 0:
+		anime, err = anilist.Mal(ctx, *mal)
+	default:
+		return errors.New("the -id or the -mal flag is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	return write(*out, anime)
+}
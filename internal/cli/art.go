@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/resource/funart"
+)
+
+func artCmd(ctx context.Context, args []string) error {
+	var (
+		fs   = flag.NewFlagSet("art", flag.ContinueOnError)
+		tmdb = fs.Int("tmdb", 0, "TMDB id of a movie")
+		tvdb = fs.Int("tvdb", 0, "TVDB id of a show")
+		out  = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	switch {
+	case *tvdb != 0:
+		art, err := funart.TV(ctx, *tvdb)
+		if err != nil {
+			return err
+		}
+		return write(*out, art)
+	case *tmdb != 0:
+		art, err := funart.Movie(ctx, *tmdb)
+		if err != nil {
+			return err
+		}
+		return write(*out, art)
+	}
+
+	return errors.New("the -tvdb or the -tmdb flag is required")
+}
@@ -0,0 +1,87 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/batch"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+func batchCmd(ctx context.Context, args []string) error {
+	var (
+		fs         = flag.NewFlagSet("batch", flag.ContinueOnError)
+		in         = fs.String("in", "", "JSONL file of jobs, stdin when empty")
+		out        = fs.String("out", "", "JSONL file the results are appended to, required")
+		checkpoint = fs.String("checkpoint", "", "file of the finished lines, defaults to the output file with .checkpoint")
+		workers    = fs.Int("workers", 2, "number of anime scraped at the same time")
+		providers  = fs.String("providers", "", "comma separated providers, empty means all: "+strings.Join(scrape.Names(), ","))
+		parallel   = fs.Int("parallel", 0, "number of providers running at once, 0 means all")
+		per        = fs.Int("concurrency", 1, "number of episode pages per provider fetched at once")
+		timeout    = fs.Duration("timeout", 10*time.Minute, "time limit of each provider")
+		update     = fs.Bool("incremental", false, "only scrape the episodes missing from the store or older than -max-age")
+		maxAge     = fs.Duration("max-age", 7*24*time.Hour, "age after which stored embeds are scraped again, 0 means never")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *out == "" {
+		return errors.New("the -out flag is required")
+	}
+	if *update && storage == nil {
+		return errors.New("the -incremental flag needs STORE_PATH to be set")
+	}
+	if *checkpoint == "" {
+		*checkpoint = *out + ".checkpoint"
+	}
+
+	var input io.Reader = os.Stdin
+	if *in != "" {
+		file, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		input = file
+	}
+
+	output, err := os.OpenFile(*out, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return err
+	}
+	defer output.Close()
+
+	opts := &scrape.Options{
+		Parallel:    *parallel,
+		Timeout:     *timeout,
+		Concurrency: make(map[string]int),
+	}
+	if *providers != "" {
+		for _, v := range strings.Split(*providers, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				opts.Providers = append(opts.Providers, v)
+			}
+		}
+	}
+	for _, v := range scrape.Names() {
+		opts.Concurrency[v] = *per
+	}
+
+	report, err := batch.Run(ctx, input, output, &batch.Options{
+		Workers:     *workers,
+		Checkpoint:  *checkpoint,
+		Scrape:      opts,
+		Store:       storage,
+		Incremental: *update,
+		MaxAge:      *maxAge,
+	})
+	if report != nil {
+		logger.Info("batch report", "done", report.Done, "skipped", report.Skipped, "failed", report.Failed)
+	}
+	return err
+}
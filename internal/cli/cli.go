@@ -0,0 +1,187 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/mal"
+	"github.com/anicine/anicine-scraper/version"
+)
+
+var logger = slog.Default().WithGroup("[CLI]")
+
+type command struct {
+	name  string
+	usage string
+	run   func(ctx context.Context, args []string) error
+}
+
+var commands = []command{
+	{"scrape", "scrape the embeds of the episodes from every provider", scrapeCmd},
+	{"map", "find the ids of the anime on the mapping sites", mapCmd},
+	{"themes", "fetch the openings and endings of the anime", themesCmd},
+	{"art", "fetch the fanart images of the anime", artCmd},
+	{"tmdb", "fetch the show or the movie from TMDB with its seasons and episodes", tmdbCmd},
+	{"tvdb", "fetch the series and its episodes from TVDB in one of its orders", tvdbCmd},
+	{"mal", "fetch the anime from MyAnimeList with the info to scrape it", malCmd},
+	{"anilist", "fetch the anime with its characters and relations from AniList", anilistCmd},
+	{"simkl", "fetch the ids of the anime on the other sites and its episodes from Simkl", simklCmd},
+	{"resolve", "find the direct streams and subtitles behind an embed url", resolveCmd},
+	{"inspect", "read the renditions, tracks and duration of an HLS or DASH manifest", inspectCmd},
+	{"verify", "check the links of a scrape result and drop the dead ones", verifyCmd},
+	{"batch", "scrape every anime of a JSONL file, resumable with a checkpoint", batchCmd},
+	{"merge", "merge several anime JSON files into one", mergeCmd},
+	{"translate", "translate a title and an overview in every language", translateCmd},
+	{"serve", "serve the scrapers and the resources over HTTP", serveCmd},
+}
+
+// Run parses the global flags, loads the configuration and runs the sub command.
+func Run(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("anicine-scraper", flag.ContinueOnError)
+	var (
+		path = fs.String("config", ".env", "path of the configuration file, skipped if missing")
+		show = fs.Bool("version", false, "print the version and exit")
+	)
+	fs.Usage = func() {
+		out := fs.Output()
+		fmt.Fprintf(out, "usage: anicine-scraper [-config file] <command> [flags]\n\ncommands:\n")
+		for _, v := range commands {
+			fmt.Fprintf(out, "  %-10s %s\n", v.name, v.usage)
+		}
+		fmt.Fprintf(out, "\nglobal flags:\n")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *show {
+		fmt.Printf("%s (%s) %s\n", version.Version, version.CommitHash, version.BuildTimestamp)
+		return nil
+	}
+	if fs.NArg() == 0 {
+		fs.Usage()
+		return errors.New("no command was given")
+	}
+
+	name := fs.Arg(0)
+	for _, v := range commands {
+		if v.name != name {
+			continue
+		}
+
+		cleanup, err := Setup(*path)
+		if err != nil {
+			return err
+		}
+		defer cleanup()
+
+		return v.run(ctx, fs.Args()[1:])
+	}
+
+	fs.Usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+// animeFlags are the flags every command uses to describe the anime.
+type animeFlags struct {
+	mal   int
+	title string
+	kind  string
+	start string
+	end   string
+}
+
+func (x *animeFlags) bind(fs *flag.FlagSet) {
+	fs.IntVar(&x.mal, "mal", 0, "MyAnimeList id of the anime")
+	fs.StringVar(&x.title, "title", "", "romanji title of the anime")
+	fs.StringVar(&x.kind, "type", "", "type of the anime: tv, movie, ova, ona, special, tv when empty")
+	fs.StringVar(&x.start, "start", "", "start date of the anime as YYYY-MM-DD, YYYY-MM or YYYY")
+	fs.StringVar(&x.end, "end", "", "end date of the anime as YYYY-MM-DD, YYYY-MM or YYYY")
+}
+
+// info builds the anime info the scrapers and the mappers expect. Without a title the
+// anime is read from MyAnimeList by its id, the other flags still override it.
+func (x *animeFlags) info(ctx context.Context) (*models.AnimeInfo, error) {
+	sd, err := analyze.ExtractAnimeDate(x.start)
+	if err != nil {
+		return nil, err
+	}
+	ed, err := analyze.ExtractAnimeDate(x.end)
+	if err != nil {
+		return nil, err
+	}
+
+	if x.title == "" {
+		if x.mal == 0 {
+			return nil, errors.New("the -title or the -mal flag is required")
+		}
+
+		info, err := mal.Info(ctx, x.mal)
+		if err != nil {
+			return nil, err
+		}
+		if x.kind != "" {
+			info.Type = strings.ToLower(x.kind)
+		}
+		if x.start != "" {
+			info.SD = sd
+		}
+		if x.end != "" {
+			info.ED = ed
+		}
+		return info, nil
+	}
+
+	kind := strings.ToLower(x.kind)
+	if kind == "" {
+		kind = "tv"
+	}
+
+	return &models.AnimeInfo{
+		Title: x.title,
+		Query: analyze.CleanTitle(x.title),
+		Type:  kind,
+		MalID: x.mal,
+		SD:    sd,
+		ED:    ed,
+	}, nil
+}
+
+// set reports whether the flag was given on the command line.
+func set(fs *flag.FlagSet, name string) bool {
+	found := false
+	fs.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			found = true
+		}
+	})
+	return found
+}
+
+// write encodes the data as indented JSON into the file, or stdout when the path is empty.
+func write(path string, data any) error {
+	var out io.Writer = os.Stdout
+	if path != "" {
+		file, err := os.Create(path)
+		if err != nil {
+			return err
+		}
+		defer file.Close()
+		out = file
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	enc.SetEscapeHTML(false)
+	return enc.Encode(data)
+}
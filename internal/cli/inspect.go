@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/resolve"
+)
+
+func inspectCmd(ctx context.Context, args []string) error {
+	var (
+		fs      = flag.NewFlagSet("inspect", flag.ContinueOnError)
+		link    = fs.String("link", "", "HLS or DASH manifest url, required")
+		referer = fs.String("referer", "", "referer the stream expects")
+		runtime = fs.Int("runtime", 0, "runtime of the episode in minutes to check the duration against")
+		out     = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *link == "" {
+		return errors.New("the -link flag is required")
+	}
+
+	var headers map[string]string
+	if *referer != "" {
+		headers = map[string]string{"Referer": *referer}
+	}
+	manifest, err := resolve.Inspect(ctx, *link, headers)
+	if err != nil {
+		return err
+	}
+	if !resolve.MatchesRuntime(manifest, *runtime) {
+		logger.Warn("the duration does not match the runtime", "duration", manifest.Duration, "runtime", *runtime)
+	}
+
+	return write(*out, manifest)
+}
@@ -0,0 +1,38 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/mal"
+)
+
+func malCmd(ctx context.Context, args []string) error {
+	var (
+		fs   = flag.NewFlagSet("mal", flag.ContinueOnError)
+		id   = fs.Int("id", 0, "MyAnimeList id of the anime")
+		only = fs.Bool("info", false, "write only the info the other commands expect")
+		out  = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *id <= 0 {
+		return errors.New("the -id flag is required")
+	}
+
+	anime, info, err := mal.Fetch(ctx, *id)
+	if err != nil {
+		return err
+	}
+	if *only {
+		return write(*out, info)
+	}
+
+	return write(*out, struct {
+		Info  *models.AnimeInfo
+		Anime *models.Anime
+	}{info, anime})
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/mapping"
+)
+
+func mapCmd(ctx context.Context, args []string) error {
+	var (
+		fs        = flag.NewFlagSet("map", flag.ContinueOnError)
+		anime     animeFlags
+		livechart = fs.Int("livechart", 0, "known livechart id to check first")
+		notifymoe = fs.String("notifymoe", "", "known notify.moe id to check first")
+		out       = fs.String("out", "", "output file, stdout when empty")
+	)
+	anime.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info, err := anime.info(ctx)
+	if err != nil {
+		return err
+	}
+
+	resource, err := mapping.All(ctx, info, *livechart, *notifymoe)
+	if err != nil {
+		return err
+	}
+	if storage != nil && info.MalID > 0 {
+		if err = storage.SaveResource(info.MalID, resource); err != nil {
+			return err
+		}
+	}
+
+	return write(*out, resource)
+}
@@ -0,0 +1,62 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+func mergeCmd(ctx context.Context, args []string) error {
+	var (
+		fs     = flag.NewFlagSet("merge", flag.ContinueOnError)
+		out    = fs.String("out", "", "output file, stdout when empty")
+		number = fs.Bool("tvdb", settings.TVDBKey != "", "number the episodes with the TVDB series of the merged anime, on when TVDB_KEY is set")
+		offset = fs.Int("offset", 0, "absolute number of the last TVDB episode before the anime, with -tvdb")
+	)
+	fs.Usage = func() {
+		fs.Output().Write([]byte("usage: anicine-scraper merge [-tvdb] [-offset n] [-out file] anime.json...\n"))
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return errors.New("at least one anime JSON file is required")
+	}
+
+	var anime []*models.Anime
+	for _, v := range fs.Args() {
+		data, err := os.ReadFile(v)
+		if err != nil {
+			return err
+		}
+
+		item := new(models.Anime)
+		if err = json.Unmarshal(data, item); err != nil {
+			return err
+		}
+		anime = append(anime, item)
+	}
+
+	result := analyze.MergeAnime(anime...)
+	if *number && result != nil && result.Resources.TVDBID != 0 {
+		if err := numberTVDB(ctx, result, *offset); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return err
+			}
+			logger.Warn("cannot number the episodes with TVDB", "TVDB", result.Resources.TVDBID, "error", err)
+		}
+	}
+	if storage != nil && result != nil && result.Resources.Mal > 0 {
+		if err := storage.SaveAnime(result.Resources.Mal, result); err != nil {
+			return err
+		}
+	}
+
+	return write(*out, result)
+}
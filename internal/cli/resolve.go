@@ -0,0 +1,32 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resolve"
+)
+
+func resolveCmd(ctx context.Context, args []string) error {
+	var (
+		fs      = flag.NewFlagSet("resolve", flag.ContinueOnError)
+		source  = fs.String("source", "", "embed url to resolve, required")
+		referer = fs.String("referer", "", "referer the embed expects")
+		out     = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *source == "" {
+		return errors.New("the -source flag is required")
+	}
+
+	media, err := resolve.Resolve(ctx, models.AnimeVideo{Source: *source, Referer: *referer})
+	if err != nil {
+		return err
+	}
+
+	return write(*out, media)
+}
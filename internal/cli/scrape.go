@@ -0,0 +1,128 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/resolve"
+	"github.com/anicine/anicine-scraper/scrape"
+	"github.com/anicine/anicine-scraper/store"
+	"github.com/anicine/anicine-scraper/verify"
+)
+
+func scrapeCmd(ctx context.Context, args []string) error {
+	var (
+		fs        = flag.NewFlagSet("scrape", flag.ContinueOnError)
+		anime     animeFlags
+		episodes  = fs.String("episodes", "1", "episodes to scrape, like 1-12,14")
+		providers = fs.String("providers", "", "comma separated providers, empty means all: "+strings.Join(scrape.Names(), ","))
+		parallel  = fs.Int("parallel", 0, "number of providers running at once, 0 means all")
+		per       = fs.Int("concurrency", 1, "number of episode pages per provider fetched at once")
+		timeout   = fs.Duration("timeout", 10*time.Minute, "time limit of each provider")
+		update    = fs.Bool("incremental", false, "only scrape the episodes missing from the store, with dead links or older than -max-age, without -episodes every aired one")
+		maxAge    = fs.Duration("max-age", 7*24*time.Hour, "age after which stored embeds are scraped again, 0 means never")
+		check     = fs.Bool("verify", false, "check every link and drop the dead ones")
+		direct    = fs.Bool("resolve", false, "resolve the embeds to set their real quality")
+		dedup     = fs.Bool("merge", false, "drop the videos several providers share and rank the rest")
+		languages = fs.String("languages", "", "comma separated ISO 639-1 codes ranked first by -merge, like en,ar")
+		out       = fs.String("out", "", "output file, stdout when empty")
+	)
+	anime.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info, err := anime.info(ctx)
+	if err != nil {
+		return err
+	}
+
+	opts := &scrape.Options{
+		Parallel:    *parallel,
+		Timeout:     *timeout,
+		Concurrency: make(map[string]int),
+	}
+	if *providers != "" {
+		for _, v := range strings.Split(*providers, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				opts.Providers = append(opts.Providers, v)
+			}
+		}
+	}
+	for _, v := range scrape.Names() {
+		opts.Concurrency[v] = *per
+	}
+
+	list := analyze.ExtractIntsWithRanges(*episodes)
+	if *update {
+		if storage == nil || info.MalID <= 0 {
+			return errors.New("the -incremental flag needs the -mal flag and STORE_PATH to be set")
+		}
+		if !set(fs, "episodes") {
+			list = nil
+		}
+		pending, err := storage.Restrict(info, list, opts, *maxAge)
+		if errors.Is(err, store.ErrNoEpisodes) {
+			return errors.New("no episode list is stored for the anime, give -episodes or run the merge command first")
+		}
+		if err != nil {
+			return err
+		}
+		if !pending {
+			logger.Info("every episode is up to date", "title", info.Title)
+			return nil
+		}
+	}
+
+	result, err := scrape.RunAll(ctx, info, list, opts)
+	if err != nil {
+		return err
+	}
+	for k, v := range result.Errors {
+		logger.Warn("provider returned no result", "name", k, "error", v)
+	}
+	var links []*verify.Link
+	if *check {
+		links = verify.Prune(ctx, result, 0)
+		if storage != nil {
+			if err = storage.SaveChecks(links); err != nil {
+				return err
+			}
+		}
+	}
+	if *direct {
+		resolve.Update(ctx, result, 0)
+	}
+	// the store keeps every provider's episodes, merging moves a shared video under a single
+	// provider and the others would look missing to -incremental.
+	if storage != nil && info.MalID > 0 {
+		if err = storage.SaveResult(info.MalID, result); err != nil {
+			return err
+		}
+		if err = storage.SaveStates(info.MalID, links); err != nil {
+			return err
+		}
+	}
+	if *dedup {
+		rank := &analyze.VideoRank{
+			Alive: verify.States(links),
+		}
+		for _, v := range strings.Split(*languages, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				rank.Languages = append(rank.Languages, strings.ToLower(v))
+			}
+		}
+		if storage != nil {
+			if rank.Hosts, err = storage.Reliability(); err != nil {
+				return err
+			}
+		}
+		result.Merge(rank)
+	}
+
+	return write(*out, result)
+}
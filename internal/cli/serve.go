@@ -0,0 +1,35 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/server"
+)
+
+func serveCmd(ctx context.Context, args []string) error {
+	// SERVER_RATE=0 turns the limit off, only a missing one falls back to the default
+	limit := 30.0
+	if settings.ServerRate != nil {
+		limit = *settings.ServerRate
+	}
+
+	var (
+		fs   = flag.NewFlagSet("serve", flag.ContinueOnError)
+		addr = fs.String("addr", ":8080", "address to listen on")
+		cert = fs.String("cert", settings.CertFile, "TLS certificate file, plain HTTP when empty")
+		key  = fs.String("key", settings.KeyFile, "TLS key file, plain HTTP when empty")
+		rate = fs.Float64("rate", limit, "requests per minute allowed to each client, 0 for no limit")
+		jobs = fs.Int("jobs", 4, "requests reaching the sites at once")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	return server.New(*addr, *cert, *key, server.Limits{
+		Token:       settings.ServerToken,
+		Rate:        *rate,
+		Burst:       5,
+		Concurrency: *jobs,
+	}).Run(ctx)
+}
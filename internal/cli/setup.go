@@ -0,0 +1,158 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"io/fs"
+	"os"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/config"
+	"github.com/anicine/anicine-scraper/resource/funart"
+	"github.com/anicine/anicine-scraper/resource/mal"
+	"github.com/anicine/anicine-scraper/resource/simkl"
+	"github.com/anicine/anicine-scraper/resource/tmdb"
+	"github.com/anicine/anicine-scraper/resource/tvdb"
+	"github.com/anicine/anicine-scraper/store"
+)
+
+// proxyCheck is the page asked through the ejected proxies when PROXY_CHECK_URL is not set.
+const proxyCheck = "https://www.gstatic.com/generate_204"
+
+var (
+	// settings is the configuration applied by the last call of Apply.
+	settings config.Config
+	// storage keeps the results of the commands, nil when no store path is set.
+	storage *store.Store
+)
+
+// Setup loads the configuration file and applies it to the client and the resources.
+// A missing file leaves the defaults in place. The returned function saves the sessions.
+func Setup(path string) (func(), error) {
+	cleanup := func() {}
+	if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+		logger.Warn("no configuration file", "path", path)
+		return cleanup, nil
+	}
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		return nil, err
+	}
+	return Apply(cfg)
+}
+
+// Apply configures the client and the resources from the configuration.
+func Apply(cfg *config.Config) (func(), error) {
+	cleanup := func() {}
+	settings = *cfg
+
+	funart.SetTokens(cfg.FunArtTokens...)
+	tmdb.SetKey(cfg.TMDBKey)
+	tvdb.SetKey(cfg.TVDBKey)
+	simkl.SetTokens(cfg.SimklTokens...)
+	if cfg.JikanURL != "" {
+		if err := mal.SetEndpoint(cfg.JikanURL); err != nil {
+			return nil, err
+		}
+	}
+
+	for host, p := range cfg.RateLimits {
+		policy := client.Policy{Rate: p.Rate, Burst: p.Burst, Concurrency: p.Concurrency}
+		if host == "*" {
+			client.SetDefaultPolicy(policy)
+		} else {
+			client.SetPolicy(host, policy)
+		}
+	}
+
+	if len(cfg.Proxies) > 0 {
+		rotation := client.RotateRequest
+		if cfg.Rotation == "host" {
+			rotation = client.RotateHost
+		}
+
+		pool, err := client.NewPool(rotation, cfg.Proxies...)
+		if err != nil {
+			return nil, err
+		}
+
+		// the ejected proxies are checked again and the circuit renewed until the cleanup
+		ctx, cancel := context.WithCancel(context.Background())
+		cleanup = cancel
+		target := cfg.ProxyCheck
+		if target == "" {
+			target = proxyCheck
+		}
+		go pool.Watch(ctx, target, time.Minute)
+
+		if cfg.TorControl != "" {
+			tor := &client.Tor{
+				Addr:       cfg.TorControl,
+				Password:   cfg.TorPassword,
+				CookieFile: cfg.TorCookie,
+			}
+			pool.SetTor(tor)
+			if cfg.TorRotate > 0 {
+				go tor.Rotate(ctx, cfg.TorRotate)
+			}
+		}
+		client.SetPool(pool)
+	}
+
+	if cfg.CacheDir != "" {
+		cache, err := client.NewCache(cfg.CacheDir, cfg.CacheTTL)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		for host, ttl := range cfg.CacheTTLs {
+			cache.SetTTL(host, ttl)
+		}
+		if cfg.CachePurge > 0 {
+			go func() {
+				if err := cache.Purge(time.Now().Add(-cfg.CachePurge)); err != nil {
+					logger.Error("cannot purge the cache", "path", cfg.CacheDir, "error", err)
+				}
+			}()
+		}
+		client.SetCache(cache)
+	}
+
+	if cfg.StorePath != "" {
+		s, err := store.Open(cfg.StorePath)
+		if err != nil {
+			cleanup()
+			return nil, err
+		}
+		storage = s
+		previous := cleanup
+		cleanup = func() {
+			if err := s.Close(); err != nil {
+				logger.Error("cannot close the store", "path", cfg.StorePath, "error", err)
+			}
+			previous()
+		}
+	}
+
+	if cfg.CookieFile != "" {
+		jar := client.DefaultJar()
+		if err := jar.Load(cfg.CookieFile); err != nil {
+			cleanup()
+			return nil, err
+		}
+		// the sites of the saved cookies, such as a Cloudflare clearance copied from a
+		// browser, get them back on every request
+		client.SetJar(jar, jar.Sites()...)
+		previous := cleanup
+		cleanup = func() {
+			if err := jar.Save(cfg.CookieFile); err != nil {
+				logger.Error("cannot save the cookies", "path", cfg.CookieFile, "error", err)
+			}
+			previous()
+		}
+	}
+
+	return cleanup, nil
+}
@@ -0,0 +1,43 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/resource/simkl"
+)
+
+func simklCmd(ctx context.Context, args []string) error {
+	var (
+		fs       = flag.NewFlagSet("simkl", flag.ContinueOnError)
+		anime    animeFlags
+		episodes = fs.Bool("episodes", false, "add the episodes with their simkl ids")
+		out      = fs.String("out", "", "output file, stdout when empty")
+	)
+	anime.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info, err := anime.info(ctx)
+	if err != nil {
+		return err
+	}
+
+	id, err := simkl.Resolve(ctx, info)
+	if err != nil {
+		return err
+	}
+	data, err := simkl.Anime(ctx, id)
+	if err != nil {
+		return err
+	}
+	if *episodes {
+		data.Episodes, err = simkl.Episodes(ctx, id)
+		if err != nil {
+			return err
+		}
+	}
+
+	return write(*out, data)
+}
@@ -0,0 +1,39 @@
+package cli
+
+import (
+	"context"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/resource/anithms"
+)
+
+func themesCmd(ctx context.Context, args []string) error {
+	var (
+		fs      = flag.NewFlagSet("themes", flag.ContinueOnError)
+		anime   animeFlags
+		english = fs.String("english", "", "english title of the anime")
+		romanji = fs.String("romanji", "", "romanji title of the anime, defaults to -title")
+		out     = fs.String("out", "", "output file, stdout when empty")
+	)
+	anime.bind(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	info, err := anime.info(ctx)
+	if err != nil {
+		return err
+	}
+	if *romanji == "" {
+		*romanji = info.Title
+	}
+
+	period := analyze.ExtractAnimePeriod(info.SD)
+	themes, err := anithms.Fetch(ctx, info.Title, *english, *romanji, info.SD.Year, period.Season)
+	if err != nil {
+		return err
+	}
+
+	return write(*out, themes)
+}
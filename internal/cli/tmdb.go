@@ -0,0 +1,44 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/tmdb"
+)
+
+func tmdbCmd(ctx context.Context, args []string) error {
+	var (
+		fs    = flag.NewFlagSet("tmdb", flag.ContinueOnError)
+		id    = fs.Int64("id", 0, "TMDB id of the show or of the movie")
+		title = fs.String("title", "", "title to search when no id is given")
+		year  = fs.Int("year", 0, "year of the first air date or of the release, 0 to ignore it")
+		movie = fs.Bool("movie", false, "look for a movie instead of a show")
+		out   = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	var (
+		anime *models.Anime
+		err   error
+	)
+	switch {
+	case *id != 0 && *movie:
+		anime, err = tmdb.Movie(ctx, *id)
+	case *id != 0:
+		anime, err = tmdb.TV(ctx, *id)
+	case *title != "":
+		anime, err = tmdb.Find(ctx, *title, *year, *movie)
+	default:
+		return errors.New("the -id or the -title flag is required")
+	}
+	if err != nil {
+		return err
+	}
+
+	return write(*out, anime)
+}
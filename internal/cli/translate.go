@@ -0,0 +1,26 @@
+package cli
+
+import (
+	"context"
+	"errors"
+	"flag"
+
+	"github.com/anicine/anicine-scraper/internal/shared"
+)
+
+func translateCmd(_ context.Context, args []string) error {
+	var (
+		fs       = flag.NewFlagSet("translate", flag.ContinueOnError)
+		title    = fs.String("title", "", "english title to translate")
+		overview = fs.String("overview", "", "english overview to translate")
+		out      = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *title == "" && *overview == "" {
+		return errors.New("the -title or the -overview flag is required")
+	}
+
+	return write(*out, shared.Translate(*title, *overview))
+}
@@ -0,0 +1,81 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/tvdb"
+)
+
+func tvdbCmd(ctx context.Context, args []string) error {
+	var (
+		fs     = flag.NewFlagSet("tvdb", flag.ContinueOnError)
+		id     = fs.Int64("id", 0, "TVDB id of the series, read from the anime when empty")
+		order  = fs.String("order", tvdb.OrderAired, "season type of the episodes: default, official, absolute, dvd")
+		anime  = fs.String("anime", "", "anime JSON file whose episodes get their TVDB ids, seasons and absolute numbers")
+		offset = fs.Int("offset", 0, "absolute number of the last TVDB episode before the anime, with -anime")
+		out    = fs.String("out", "", "output file, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if *anime != "" {
+		data, err := os.ReadFile(*anime)
+		if err != nil {
+			return err
+		}
+		item := new(models.Anime)
+		if err = json.Unmarshal(data, item); err != nil {
+			return err
+		}
+		if *id != 0 {
+			item.Resources.TVDBID = *id
+		}
+		if err = numberTVDB(ctx, item, *offset); err != nil {
+			return err
+		}
+		return write(*out, item)
+	}
+
+	if *id == 0 {
+		return errors.New("the -id or the -anime flag is required")
+	}
+
+	series, err := tvdb.Series(ctx, *id)
+	if err != nil {
+		return err
+	}
+	episodes, err := tvdb.Episodes(ctx, *id, *order)
+	if err != nil {
+		return err
+	}
+
+	return write(*out, struct {
+		Series   *tvdb.TVDBSeries
+		Episodes []tvdb.TVDBEpisode
+	}{series, episodes})
+}
+
+// numberTVDB fills the TVDB id, the season and the absolute number of the episodes of the anime
+// from the TVDB series the anime maps to.
+func numberTVDB(ctx context.Context, anime *models.Anime, offset int) error {
+	if anime.Resources.TVDBID == 0 {
+		return errors.New("the anime has no TVDB id")
+	}
+	if len(anime.Episodes) == 0 {
+		return nil
+	}
+
+	order, err := tvdb.Order(ctx, anime.Resources.TVDBID)
+	if err != nil {
+		return err
+	}
+	found := order.Fill(anime.Episodes, offset)
+	logger.Info("episodes were numbered", "TVDB", anime.Resources.TVDBID, "found", found, "total", len(anime.Episodes))
+	return nil
+}
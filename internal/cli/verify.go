@@ -0,0 +1,57 @@
+package cli
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"os"
+
+	"github.com/anicine/anicine-scraper/scrape"
+	"github.com/anicine/anicine-scraper/verify"
+)
+
+func verifyCmd(ctx context.Context, args []string) error {
+	var (
+		fs       = flag.NewFlagSet("verify", flag.ContinueOnError)
+		in       = fs.String("in", "", "JSON result of the scrape command, required")
+		parallel = fs.Int("parallel", 8, "number of links checked at once")
+		pruned   = fs.String("pruned", "", "file the result without the dead links is written to")
+		malID    = fs.Int("mal", 0, "MyAnimeList id of the result, removes its dead links from the store so -incremental scrapes them again")
+		out      = fs.String("out", "", "output file of the state of every link, stdout when empty")
+	)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *in == "" {
+		return errors.New("the -in flag is required")
+	}
+
+	data, err := os.ReadFile(*in)
+	if err != nil {
+		return err
+	}
+	result := new(scrape.Result)
+	if err = json.Unmarshal(data, result); err != nil {
+		return err
+	}
+
+	links := verify.Prune(ctx, result, *parallel)
+	if storage != nil {
+		if err = storage.SaveChecks(links); err != nil {
+			return err
+		}
+		if *malID > 0 {
+			if err = storage.SaveStates(*malID, links); err != nil {
+				return err
+			}
+		}
+	}
+	if *pruned != "" {
+		if err = write(*pruned, result); err != nil {
+			return err
+		}
+	}
+
+	return write(*out, links)
+}
@@ -6,7 +6,9 @@ import (
 	"log/slog"
 	"os"
 	"regexp"
+	"strconv"
 	"strings"
+	"time"
 )
 
 var (
@@ -14,12 +16,36 @@ var (
 	logger  = slog.Default().WithGroup("[CONFIG]")
 )
 
+// Policy is the rate limit of a host, see client.Policy.
+type Policy struct {
+	Rate        float64
+	Burst       int
+	Concurrency int
+}
+
 type Config struct {
 	Proxy        string
+	Proxies      []string
+	Rotation     string
+	ProxyCheck   string
+	TorControl   string
+	TorPassword  string
+	TorCookie    string
+	TorRotate    time.Duration
+	CookieFile   string
+	CacheDir     string
+	CacheTTL     time.Duration
+	CacheTTLs    map[string]time.Duration
+	CachePurge   time.Duration
+	RateLimits   map[string]Policy
+	StorePath    string
 	CertFile     string
 	KeyFile      string
+	ServerToken  string
+	ServerRate   *float64
 	TMDBKey      string
 	TVDBKey      string
+	JikanURL     string
 	SimklTokens  []string
 	FunArtTokens []string
 }
@@ -66,11 +92,62 @@ func Load(path string) (*Config, error) {
 			}
 			config.TVDBKey = value
 		case "PROXY_URL":
-			if value == "" {
+			var proxies []string
+			for _, p := range strings.Split(value, ",") {
+				if p = strings.TrimSpace(p); p != "" {
+					proxies = append(proxies, p)
+				}
+			}
+			if len(proxies) == 0 {
 				logger.Warn("no proxy url value", "key", key)
 			} else {
 				logger.Info("value was set", "key", key)
-				config.Proxy = value
+				config.Proxy = proxies[0]
+				config.Proxies = proxies
+			}
+		case "PROXY_ROTATION":
+			if value == "" {
+				logger.Warn("no proxy rotation value", "key", key)
+			} else if value != "request" && value != "host" {
+				return nil, errors.New("the proxy rotation must be 'request' or 'host'")
+			} else {
+				logger.Info("value was set", "key", key)
+				config.Rotation = value
+			}
+		case "PROXY_CHECK_URL":
+			if value == "" {
+				logger.Warn("no proxy check url value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.ProxyCheck = value
+			}
+		case "TOR_CONTROL":
+			if value == "" {
+				logger.Warn("no tor control address value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.TorControl = value
+			}
+		case "TOR_PASSWORD":
+			if value != "" {
+				logger.Info("value was set", "key", key)
+				config.TorPassword = value
+			}
+		case "TOR_COOKIE":
+			if value != "" {
+				logger.Info("value was set", "key", key)
+				config.TorCookie = value
+			}
+		case "TOR_ROTATE":
+			if value == "" {
+				logger.Warn("no tor rotation value", "key", key)
+			} else {
+				every, err := time.ParseDuration(value)
+				if err != nil {
+					return nil, errors.New("the tor rotation must be a duration like 10m")
+				}
+				logger.Info("value was set", "key", key)
+				config.TorRotate = every
 			}
 		case "CERT_FILE":
 			if value == "" {
@@ -86,6 +163,109 @@ func Load(path string) (*Config, error) {
 				logger.Info("value was set", "key", key)
 				config.KeyFile = value
 			}
+		case "SERVER_TOKEN":
+			if value == "" {
+				logger.Warn("no server token value, the server is open to everyone", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.ServerToken = value
+			}
+		case "SERVER_RATE":
+			if value != "" {
+				rate, err := strconv.ParseFloat(value, 64)
+				if err != nil || rate < 0 {
+					return nil, errors.New("the server rate must be a number of requests per minute")
+				}
+				logger.Info("value was set", "key", key)
+				config.ServerRate = &rate
+			}
+		case "COOKIE_FILE":
+			if value == "" {
+				logger.Warn("no cookie file value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.CookieFile = value
+			}
+		case "CACHE_DIR":
+			if value == "" {
+				logger.Warn("no cache directory value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.CacheDir = value
+			}
+		case "CACHE_TTL":
+			if value == "" {
+				logger.Warn("no cache ttl value", "key", key)
+			} else {
+				ttl, err := time.ParseDuration(value)
+				if err != nil {
+					return nil, errors.New("the cache ttl must be a duration like 24h")
+				}
+				logger.Info("value was set", "key", key)
+				config.CacheTTL = ttl
+			}
+		case "CACHE_TTLS":
+			if value == "" {
+				logger.Warn("no cache ttls value", "key", key)
+				continue
+			}
+			config.CacheTTLs = make(map[string]time.Duration)
+			for _, v := range strings.Split(value, ",") {
+				host, ttl, ok := strings.Cut(strings.TrimSpace(v), "=")
+				host = strings.ToLower(strings.TrimSpace(host))
+				if !ok || host == "" {
+					return nil, errors.New("the cache ttls must look like host=24h or host=off")
+				}
+				if ttl = strings.TrimSpace(ttl); ttl == "off" {
+					config.CacheTTLs[host] = -1
+					continue
+				}
+				d, err := time.ParseDuration(ttl)
+				if err != nil {
+					return nil, errors.New("the cache ttls must look like host=24h or host=off")
+				}
+				config.CacheTTLs[host] = d
+			}
+			logger.Info("value was set", "key", key)
+		case "CACHE_PURGE":
+			if value == "" {
+				logger.Warn("no cache purge value", "key", key)
+			} else {
+				age, err := time.ParseDuration(value)
+				if err != nil {
+					return nil, errors.New("the cache purge must be a duration like 720h")
+				}
+				logger.Info("value was set", "key", key)
+				config.CachePurge = age
+			}
+		case "RATE_LIMITS":
+			if value == "" {
+				logger.Warn("no rate limits value", "key", key)
+				continue
+			}
+			config.RateLimits = make(map[string]Policy)
+			for _, v := range strings.Split(value, ",") {
+				host, p, err := policy(v)
+				if err != nil {
+					return nil, err
+				}
+				config.RateLimits[host] = p
+			}
+			logger.Info("value was set", "key", key)
+		case "STORE_PATH":
+			if value == "" {
+				logger.Warn("no store path value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.StorePath = value
+			}
+		case "JIKAN_URL":
+			if value == "" {
+				logger.Warn("no jikan url value", "key", key)
+			} else {
+				logger.Info("value was set", "key", key)
+				config.JikanURL = value
+			}
 		case "SIMKL_TOKENS":
 			var tokens []string
 			for _, t := range strings.Split(value, ",") {
@@ -115,3 +295,34 @@ func Load(path string) (*Config, error) {
 
 	return config, nil
 }
+
+// policy parses a rate limit written as host=rate/burst/concurrency, for example
+// api.jikan.moe=1/3/2. The host * stands for every host without its own limit.
+func policy(input string) (string, Policy, error) {
+	invalid := errors.New("the rate limits must look like host=rate/burst/concurrency")
+
+	host, value, ok := strings.Cut(strings.TrimSpace(input), "=")
+	host = strings.ToLower(strings.TrimSpace(host))
+	if !ok || host == "" {
+		return "", Policy{}, invalid
+	}
+
+	fields := strings.Split(value, "/")
+	if len(fields) != 3 {
+		return "", Policy{}, invalid
+	}
+	rate, err := strconv.ParseFloat(strings.TrimSpace(fields[0]), 64)
+	if err != nil || rate < 0 {
+		return "", Policy{}, invalid
+	}
+	burst, err := strconv.Atoi(strings.TrimSpace(fields[1]))
+	if err != nil || burst < 0 {
+		return "", Policy{}, invalid
+	}
+	concurrency, err := strconv.Atoi(strings.TrimSpace(fields[2]))
+	if err != nil || concurrency < 0 {
+		return "", Policy{}, invalid
+	}
+
+	return host, Policy{Rate: rate, Burst: burst, Concurrency: concurrency}, nil
+}
@@ -1,9 +1,132 @@
 package errs
 
-import "errors"
+import (
+	"errors"
+	"net/url"
+	"strconv"
+	"strings"
+)
 
 var (
 	ErrNotFound = errors.New("not found")
 	ErrBadData  = errors.New("bad data")
 	ErrNoData   = errors.New("no data")
+	ErrBlocked  = errors.New("blocked")
+)
+
+// Stage is the step of a provider where an error happened.
+type Stage string
+
+const (
+	StageSearch Stage = "search"
+	StageFilter Stage = "filter"
+	StageFetch  Stage = "fetch"
+	StageScrape Stage = "scrape"
 )
+
+// Error carries the context of a failure, it unwraps to its cause so
+// errors.Is keeps working against the sentinel errors.
+type Error struct {
+	Provider string
+	Stage    Stage
+	URL      string
+	Status   int
+	Err      error
+}
+
+func (e *Error) Error() string {
+	var msg []string
+	if e.Provider != "" {
+		msg = append(msg, e.Provider)
+	}
+	if e.Stage != "" {
+		msg = append(msg, string(e.Stage))
+	}
+	if e.URL != "" {
+		msg = append(msg, e.URL)
+	}
+	if e.Status != 0 {
+		msg = append(msg, "status "+strconv.Itoa(e.Status))
+	}
+	if e.Err != nil {
+		msg = append(msg, e.Err.Error())
+	}
+	return strings.Join(msg, ": ")
+}
+
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Secrets are the query parameters and the request fields that carry credentials, they are
+// hidden from the errors, the logs and the recorded cassettes.
+var Secrets = []string{"api_key", "apikey", "key", "token", "access_token", "client_id", "client_secret"}
+
+// Redact hides the credentials of the link, the password of its user and the values of the
+// query parameters such as api_key, so it can be logged or shown in an error.
+func Redact(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		link, _, _ = strings.Cut(link, "?")
+		return link
+	}
+
+	if u.RawQuery != "" {
+		query := u.Query()
+		var found bool
+		for k := range query {
+			for _, v := range Secrets {
+				if strings.EqualFold(k, v) {
+					query.Set(k, "REDACTED")
+					found = true
+				}
+			}
+		}
+		if found {
+			u.RawQuery = query.Encode()
+		}
+	}
+
+	return u.Redacted()
+}
+
+// HTTP returns an error for a failed request to the given link, see Redact.
+func HTTP(link string, status int, err error) error {
+	return &Error{
+		URL:    Redact(link),
+		Status: status,
+		Err:    err,
+	}
+}
+
+// Wrap adds the provider and the stage to the error, keeping the link and the
+// status of any HTTP error it wraps. It returns nil if the error is nil and
+// leaves errors that already name a provider untouched.
+func Wrap(err error, provider string, stage Stage) error {
+	if err == nil {
+		return nil
+	}
+
+	var e *Error
+	if errors.As(err, &e) {
+		if e.Provider != "" {
+			return err
+		}
+		if e == err {
+			err = e.Err
+		}
+		return &Error{
+			Provider: provider,
+			Stage:    stage,
+			URL:      e.URL,
+			Status:   e.Status,
+			Err:      err,
+		}
+	}
+
+	return &Error{
+		Provider: provider,
+		Stage:    stage,
+		Err:      err,
+	}
+}
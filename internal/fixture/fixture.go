@@ -0,0 +1,409 @@
+package fixture
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"path/filepath"
+	"reflect"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/mapping"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/anilist"
+	"github.com/anicine/anicine-scraper/resource/anithms"
+	"github.com/anicine/anicine-scraper/resource/funart"
+	myanimelist "github.com/anicine/anicine-scraper/resource/mal"
+	"github.com/anicine/anicine-scraper/resource/simkl"
+	"github.com/anicine/anicine-scraper/resource/tmdb"
+	"github.com/anicine/anicine-scraper/resource/tvdb"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+var logger = slog.Default().WithGroup("[FIXTURE]")
+
+// the anime every cassette is written against
+var (
+	info = &models.AnimeInfo{
+		Title: "Sousou no Frieren",
+		Query: analyze.CleanTitle("Sousou no Frieren"),
+		Type:  "tv",
+		MalID: 52991,
+		SD:    models.AnimeDate{Year: 2023, Month: 9, Day: 29},
+		ED:    models.AnimeDate{Year: 2024, Month: 3, Day: 22},
+	}
+	episodes = []int{1, 2}
+	tvdbID   = 424536
+	tmdbID   = int64(209867)
+)
+
+// Case is one function of the repository run against its cassette.
+type Case struct {
+	Name string
+	Run  func(ctx context.Context) error
+}
+
+// Report is the outcome of a case.
+type Report struct {
+	Name    string
+	Skipped bool
+	Err     error
+	Unused  []string
+}
+
+// Cases returns every scraper, mapping and resource case sorted by name.
+func Cases() []Case {
+	var cases []Case
+	for _, v := range scrape.Scrapers("") {
+		s := v
+		cases = append(cases, Case{
+			Name: "scrape-" + s.Name(),
+			Run: func(ctx context.Context) error {
+				nodes, err := s.Scrape(ctx, info, episodes)
+				if err != nil {
+					return err
+				}
+				return embeds(nodes, episodes)
+			},
+		})
+	}
+
+	cases = append(cases,
+		Case{
+			Name: "mapping-animeplanet",
+			Run: func(ctx context.Context) error {
+				resource, err := mapping.AnimePlanet(ctx, info)
+				if err != nil {
+					return err
+				}
+				// the mini anime of the same year comes first and must not match
+				return expect("anime-planet", resource.AnimePlanet, "37463")
+			},
+		},
+		Case{
+			Name: "mapping-livechart",
+			Run: func(ctx context.Context) error {
+				resource, err := mapping.LiveChart(ctx, info, 0)
+				if err != nil {
+					return err
+				}
+				if err = mal(resource); err != nil {
+					return err
+				}
+				return expect(
+					"livechart", resource.LiveChart, int64(11770),
+					"anilist", resource.AniList, 154587,
+					"anidb", resource.AniDB, 17617,
+					"kitsu", resource.Kitsu, "46474",
+					"anime-planet", resource.AnimePlanet, "frieren-beyond-journeys-end",
+				)
+			},
+		},
+		Case{
+			Name: "mapping-notifymoe",
+			Run: func(ctx context.Context) error {
+				resource, err := mapping.NotifyMoe(ctx, info, "")
+				if err != nil {
+					return err
+				}
+				if err = mal(resource); err != nil {
+					return err
+				}
+				return expect(
+					"notify.moe", resource.NotifyMoe, "ifQ3ZR6Ng",
+					"anilist", resource.AniList, 154587,
+					"anidb", resource.AniDB, 17617,
+				)
+			},
+		},
+		Case{
+			Name: "resource-mal",
+			Run: func(ctx context.Context) error {
+				anime, data, err := myanimelist.Fetch(ctx, info.MalID)
+				if err != nil {
+					return err
+				}
+				if err = mal(&anime.Resources); err != nil {
+					return err
+				}
+				if len(anime.Trailers) == 0 || len(anime.Studios) == 0 || len(anime.Relations) != 2 {
+					return fmt.Errorf("%w: %d trailers and %d relations", errs.ErrNoData, len(anime.Trailers), len(anime.Relations))
+				}
+				return expect(
+					"query", data.Query, info.Query,
+					"type", data.Type, info.Type,
+					"start", data.SD, info.SD,
+					"end", data.ED, info.ED,
+					"status", anime.Status, "finished",
+					"content rating", anime.ContentRating, "PG-13",
+					"period", anime.Period, models.AnimePeriod{Season: "fall", Year: 2023},
+					"trailer", anime.Trailers[0].HostKey, "ZEkwCGJ3o7M",
+					"studio", anime.Studios[0].Name, "Madhouse",
+				)
+			},
+		},
+		Case{
+			Name: "resource-anilist",
+			Run: func(ctx context.Context) error {
+				anime, err := anilist.Mal(ctx, info.MalID)
+				if err != nil {
+					return err
+				}
+				if err = mal(&anime.Resources); err != nil {
+					return err
+				}
+				// the characters are paged, the last one comes from the second page
+				if len(anime.Characters) != 3 || len(anime.Characters[0].VoiceActor) == 0 || len(anime.Studios) == 0 {
+					return fmt.Errorf("%w: %d characters and %d studios", errs.ErrNoData, len(anime.Characters), len(anime.Studios))
+				}
+				return expect(
+					"anilist", anime.Resources.AniList, 154587,
+					"status", anime.Status, "finished",
+					"period", anime.Period, models.AnimePeriod{Season: "fall", Year: 2023},
+					"start", anime.StartAt, info.SD,
+					"studio", anime.Studios[0].Name, "MADHOUSE",
+					"relations", len(anime.Relations), 2,
+					"tags", len(anime.Tags), 1,
+					"character", anime.Characters[0].Name.Full, "Frieren",
+					"voice actor", anime.Characters[0].VoiceActor[0].Name.Full, "Atsumi Tanezaki",
+					"last character", anime.Characters[2].Name.Full, "Himmel",
+				)
+			},
+		},
+		Case{
+			Name: "resource-anithms",
+			Run: func(ctx context.Context) error {
+				themes, err := anithms.Fetch(ctx, info.Title, "Frieren: Beyond Journey's End", info.Title, info.SD.Year, "fall")
+				if err != nil {
+					return err
+				}
+				// the first page only holds the mini anime, the show is on the next one
+				if len(themes.OP) != 1 || len(themes.ED) != 1 || len(themes.ED[0].Entries) == 0 {
+					return fmt.Errorf("%w: %d openings and %d endings", errs.ErrNoData, len(themes.OP), len(themes.ED))
+				}
+				return expect(
+					"opening", themes.OP[0].Song, "Yuusha",
+					"ending", themes.ED[0].Song, "Anytime Anywhere",
+					"videos", len(themes.ED[0].Entries[0].Videos), 1,
+					"episodes", len(themes.ED[0].Entries[0].Episodes), 15,
+				)
+			},
+		},
+		Case{
+			Name: "resource-funart",
+			Run: func(ctx context.Context) error {
+				art, err := funart.TV(ctx, tvdbID)
+				if err != nil {
+					return err
+				}
+				if len(art.HdTvLogo) == 0 || len(art.TvPoster) == 0 {
+					return fmt.Errorf("%w: %d logos and %d posters", errs.ErrNoData, len(art.HdTvLogo), len(art.TvPoster))
+				}
+				return expect(
+					"name", art.Name, "Frieren: Beyond Journey's End",
+					"logo language", art.HdTvLogo[0].Lang, "en",
+					"backgrounds", len(art.ShowBackground), 1,
+				)
+			},
+		},
+		Case{
+			Name: "resource-simkl",
+			Run: func(ctx context.Context) error {
+				anime, err := simkl.Find(ctx, info)
+				if err != nil {
+					return err
+				}
+				if err = mal(&anime.Resources); err != nil {
+					return err
+				}
+
+				list, err := simkl.Episodes(ctx, 2157632)
+				if err != nil {
+					return err
+				}
+				if len(list) != 3 {
+					return fmt.Errorf("%w: %d episodes, want 3", errs.ErrNoData, len(list))
+				}
+				return expect(
+					"AniList id", anime.Resources.AniList, 154587,
+					"TVDB id", anime.Resources.TVDBID, int64(tvdbID),
+					"TMDB id", anime.Resources.TMDBID, tmdbID,
+					"Kitsu id", anime.Resources.Kitsu, "46474",
+					"english title", first(anime.Titles.English), "Frieren: Beyond Journey's End",
+					"start", anime.StartAt, info.SD,
+					"episode id", list[1].Resources.SimklID, int64(9632842),
+					"episode number", list[1].Number, float32(2),
+					"special", list[2].Special, true,
+				)
+			},
+		},
+		Case{
+			Name: "resource-tmdb",
+			Run: func(ctx context.Context) error {
+				anime, err := tmdb.TV(ctx, tmdbID)
+				if err != nil {
+					return err
+				}
+				if len(anime.Episodes) != 2 || len(anime.InnerSeasons) != 1 || len(anime.Trailers) == 0 {
+					return fmt.Errorf("%w: %d episodes in %d seasons", errs.ErrNoData, len(anime.Episodes), len(anime.InnerSeasons))
+				}
+				second := anime.Episodes[1]
+				return expect(
+					"TVDB id", anime.Resources.TVDBID, int64(tvdbID),
+					"IMDB id", anime.Resources.IMDBID, "tt22248376",
+					"english title", first(anime.Titles.English), "Frieren: Beyond Journey's End",
+					"start", anime.StartAt, info.SD,
+					"content rating", anime.ContentRating, "TV-14",
+					"official trailer", anime.Trailers[0].HostKey, "ZEkwCGJ3o7M",
+					"episode id", second.Resources.TMDBID, int64(4655114),
+					"episode title", second.EnTitle, "It Didn't Have to Be Magic...",
+					"absolute number", second.AbsoluteNumber, float32(2),
+					"episode runtime", second.Runtime, 24,
+				)
+			},
+		},
+		Case{
+			Name: "resource-tvdb",
+			Run: func(ctx context.Context) error {
+				order, err := tvdb.Order(ctx, int64(tvdbID))
+				if err != nil {
+					return err
+				}
+				third, ok := order.Absolute(3)
+				if !ok {
+					return errs.ErrNoData
+				}
+
+				list := []models.AnimeEpisode{{Number: 2}, {Number: 1, Special: true}, {Number: 4}}
+				if found := order.Fill(list, 0); found != 2 {
+					return fmt.Errorf("%w: %d episodes were numbered, want 2", errs.ErrBadData, found)
+				}
+				return expect(
+					"third episode", third.ID, int64(9974563),
+					"third season", third.SeasonNumber, 1,
+					"episode id", list[0].Resources.TVDBID, int64(9974562),
+					"absolute number", list[0].AbsoluteNumber, float32(2),
+					"special id", list[1].Resources.TVDBID, int64(10128012),
+					"special season", list[1].SeasonNumber, 0,
+					"unknown episode id", list[2].Resources.TVDBID, int64(0),
+				)
+			},
+		},
+	)
+
+	slices.SortFunc(cases, func(a, b Case) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return cases
+}
+
+// embeds checks that every episode asked for has at least one video or download, and that
+// every video has a source.
+func embeds(nodes *[]*scrape.EmbedNode, episodes []int) error {
+	if nodes == nil {
+		return errs.ErrNoData
+	}
+
+	found := make(map[int]bool)
+	for _, v := range *nodes {
+		if v == nil {
+			continue
+		}
+		for _, y := range append(v.Videos, v.Download...) {
+			if y.Source == "" {
+				return fmt.Errorf("%w: episode %d has a video without source", errs.ErrBadData, v.Number)
+			}
+			found[v.Number] = true
+		}
+	}
+	for _, v := range episodes {
+		if !found[v] {
+			return fmt.Errorf("%w: episode %d has no video", errs.ErrNoData, v)
+		}
+	}
+	return nil
+}
+
+// expect compares the values given as name, got, want triples and reports the first that differs.
+func expect(checks ...any) error {
+	for i := 0; i+2 < len(checks); i += 3 {
+		if !reflect.DeepEqual(checks[i+1], checks[i+2]) {
+			return fmt.Errorf("%w: %v is %#v, want %#v", errs.ErrBadData, checks[i], checks[i+1], checks[i+2])
+		}
+	}
+	return nil
+}
+
+func first(list []string) string {
+	if len(list) == 0 {
+		return ""
+	}
+	return list[0]
+}
+
+func mal(resource *models.AnimeResource) error {
+	if resource == nil || resource.Mal != info.MalID {
+		return errs.ErrBadData
+	}
+	return nil
+}
+
+// Run plays the cases whose names are given, or all of them, against the cassettes in the
+// directory. In replay mode a case without a cassette is skipped, in record mode its
+// cassette is written from the live sites.
+func Run(ctx context.Context, dir string, mode client.Mode, names ...string) []Report {
+	// the cassettes hold no keys, recording needs the real ones from the configuration
+	if mode == client.Replay {
+		funart.SetTokens("fixture")
+		tmdb.SetKey("fixture")
+		tvdb.SetKey("fixture")
+		simkl.SetTokens("fixture")
+	}
+	defer client.SetRecorder(nil)
+
+	var reports []Report
+	for _, v := range Cases() {
+		if len(names) > 0 && !slices.Contains(names, v.Name) {
+			continue
+		}
+
+		report := Report{Name: v.Name}
+		path := filepath.Join(dir, v.Name+".json")
+		rec, err := client.NewRecorder(path, mode, errs.Secrets...)
+		if err != nil {
+			if errors.Is(err, fs.ErrNotExist) {
+				report.Skipped = true
+			} else {
+				report.Err = err
+			}
+			reports = append(reports, report)
+			continue
+		}
+
+		client.SetRecorder(rec)
+		cctx, cancel := context.WithTimeout(ctx, 5*time.Minute)
+		report.Err = v.Run(cctx)
+		cancel()
+		client.SetRecorder(nil)
+
+		if mode == client.Record {
+			if err = rec.Save(); err != nil {
+				report.Err = errors.Join(report.Err, err)
+			}
+		} else {
+			report.Unused = rec.Unused()
+		}
+
+		logger.Info("case finished", "name", v.Name, "error", report.Err)
+		reports = append(reports, report)
+	}
+
+	return reports
+}
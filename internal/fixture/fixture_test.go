@@ -0,0 +1,20 @@
+package fixture
+
+import (
+	"context"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/stretchr/testify/assert"
+)
+
+// TestFixtures replays every cassette in testdata, a case without one fails.
+func TestFixtures(t *testing.T) {
+	for _, v := range Run(context.Background(), "testdata", client.Replay) {
+		t.Run(v.Name, func(t *testing.T) {
+			assert.False(t, v.Skipped, "no cassette in testdata")
+			assert.NoError(t, v.Err)
+			assert.Empty(t, v.Unused, "requests recorded but never made")
+		})
+	}
+}
@@ -0,0 +1,73 @@
+// Command replay runs the scrapers, mappings and resources against recorded HTTP cassettes.
+//
+//	go run ./internal/fixture/replay                 # replay every cassette, no network
+//	go run ./internal/fixture/replay -record scrape-gogoanime # record from the live sites
+//
+// Recording reads the api keys, the proxies and the cookies from the configuration file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/cli"
+	"github.com/anicine/anicine-scraper/internal/config"
+	"github.com/anicine/anicine-scraper/internal/fixture"
+)
+
+func main() {
+	var (
+		dir    = flag.String("dir", "internal/fixture/testdata", "directory of the cassettes")
+		record = flag.Bool("record", false, "record the cassettes from the live sites")
+		path   = flag.String("config", ".env", "configuration file used when recording")
+	)
+	flag.Parse()
+
+	mode := client.Replay
+	cleanup := func() {}
+	if *record {
+		mode = client.Record
+
+		cfg, err := config.Load(*path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "cannot load the configuration: %v\n", err)
+			os.Exit(1)
+		}
+		// the responses must come from the sites, not from the cache or the store
+		cfg.CacheDir = ""
+		cfg.StorePath = ""
+
+		if cleanup, err = cli.Apply(cfg); err != nil {
+			fmt.Fprintf(os.Stderr, "cannot apply the configuration: %v\n", err)
+			os.Exit(1)
+		}
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	var failed bool
+	for _, v := range fixture.Run(ctx, *dir, mode, flag.Args()...) {
+		switch {
+		case v.Skipped:
+			fmt.Printf("SKIP %s (no cassette)\n", v.Name)
+		case v.Err != nil:
+			failed = true
+			fmt.Printf("FAIL %s: %v\n", v.Name, v.Err)
+		default:
+			fmt.Printf("PASS %s\n", v.Name)
+			for _, y := range v.Unused {
+				fmt.Printf("     unused %s\n", y)
+			}
+		}
+	}
+
+	cleanup()
+	if failed {
+		os.Exit(1)
+	}
+}
@@ -11,6 +11,7 @@ import (
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
 	"github.com/anicine/anicine-scraper/internal/errs"
 	"github.com/anicine/anicine-scraper/internal/shared"
 	"github.com/anicine/anicine-scraper/models"
@@ -28,12 +29,12 @@ func AnimePlanet(ctx context.Context, info *models.AnimeInfo) (*models.AnimeReso
 		},
 	})
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeplanet", errs.StageSearch)
 	}
 
 	doc, err := goquery.NewDocumentFromReader(body)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeplanet", errs.StageSearch)
 	}
 
 	var (
@@ -59,7 +60,7 @@ func AnimePlanet(ctx context.Context, info *models.AnimeInfo) (*models.AnimeReso
 			}
 
 			if year == info.SD.Year {
-				sim := shared.TextAdvancedSimilarity(info.Query, nameTxt.Text())
+				sim := shared.TextAdvancedSimilarity(info.Query, analyze.CleanTitle(nameTxt.Text()))
 				if sim > 90 {
 					if data, ok := s.Attr("data-id"); ok {
 						if data != "" {
@@ -77,5 +78,5 @@ func AnimePlanet(ctx context.Context, info *models.AnimeInfo) (*models.AnimeReso
 		return resource, nil
 	}
 
-	return nil, errs.ErrNotFound
+	return nil, errs.Wrap(errs.ErrNotFound, "animeplanet", errs.StageFilter)
 }
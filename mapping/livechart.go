@@ -38,7 +38,7 @@ func LiveChart(ctx context.Context, info *models.AnimeInfo, id int) (*models.Ani
 		resource, err = x.check()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				return nil, err
+				return nil, errs.Wrap(err, "livechart", errs.StageFilter)
 			}
 		}
 		if resource != nil {
@@ -48,13 +48,13 @@ func LiveChart(ctx context.Context, info *models.AnimeInfo, id int) (*models.Ani
 
 	found, err := x.query()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "livechart", errs.StageSearch)
 	}
 	if found {
 		resource, err = x.check()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				return nil, err
+				return nil, errs.Wrap(err, "livechart", errs.StageFilter)
 			}
 		}
 		if resource != nil {
@@ -62,7 +62,11 @@ func LiveChart(ctx context.Context, info *models.AnimeInfo, id int) (*models.Ani
 		}
 	}
 
-	return nil, errs.ErrNotFound
+	if err != nil {
+		return nil, errs.Wrap(err, "livechart", errs.StageFilter)
+	}
+
+	return nil, errs.Wrap(errs.ErrNotFound, "livechart", errs.StageFilter)
 }
 
 func (x *liveChart) query() (bool, error) {
@@ -0,0 +1,72 @@
+package mapping
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/simkl"
+)
+
+var logger = slog.Default().WithGroup("[MAPPING]")
+
+// All asks every mapping site at once and merges the ids they agree on.
+// The known livechart and notify.moe ids are checked first when they are set.
+func All(ctx context.Context, info *models.AnimeInfo, livechart int, notifymoe string) (*models.AnimeResource, error) {
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		data  []*models.Anime
+		jobs  = map[string]func() (*models.AnimeResource, error){
+			"livechart": func() (*models.AnimeResource, error) {
+				return LiveChart(ctx, info, livechart)
+			},
+			"notifymoe": func() (*models.AnimeResource, error) {
+				return NotifyMoe(ctx, info, notifymoe)
+			},
+			"animeplanet": func() (*models.AnimeResource, error) {
+				return AnimePlanet(ctx, info)
+			},
+			"simkl": func() (*models.AnimeResource, error) {
+				anime, err := simkl.Find(ctx, info)
+				if err != nil {
+					return nil, err
+				}
+				return &anime.Resources, nil
+			},
+		}
+	)
+
+	for k, v := range jobs {
+		wg.Add(1)
+		go func(name string, job func() (*models.AnimeResource, error)) {
+			defer wg.Done()
+			resource, err := job()
+			if err != nil {
+				logger.Warn("mapping returned no result", "name", name, "error", err)
+				return
+			}
+
+			mutex.Lock()
+			defer mutex.Unlock()
+			data = append(data, &models.Anime{Resources: *resource})
+		}(k, v)
+	}
+	wg.Wait()
+
+	if ctx.Err() != nil {
+		return nil, context.Canceled
+	}
+	if len(data) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	resource := analyze.MergeAnimeResource(data...)
+	if resource.Mal == 0 {
+		resource.Mal = info.MalID
+	}
+	return &resource, nil
+}
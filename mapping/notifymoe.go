@@ -49,7 +49,7 @@ func NotifyMoe(ctx context.Context, info *models.AnimeInfo, id string) (*models.
 		resource, err = x.check()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				return nil, err
+				return nil, errs.Wrap(err, "notifymoe", errs.StageFilter)
 			}
 		}
 		if resource != nil {
@@ -60,14 +60,14 @@ func NotifyMoe(ctx context.Context, info *models.AnimeInfo, id string) (*models.
 	found, err := x.query()
 	if err != nil {
 		if errors.Is(err, context.Canceled) {
-			return nil, err
+			return nil, errs.Wrap(err, "notifymoe", errs.StageSearch)
 		}
 	}
 	if found {
 		resource, err = x.check()
 		if err != nil {
 			if errors.Is(err, context.Canceled) {
-				return nil, err
+				return nil, errs.Wrap(err, "notifymoe", errs.StageFilter)
 			}
 		}
 		if resource != nil {
@@ -75,7 +75,11 @@ func NotifyMoe(ctx context.Context, info *models.AnimeInfo, id string) (*models.
 		}
 	}
 
-	return nil, errs.ErrNotFound
+	if err != nil {
+		return nil, errs.Wrap(err, "notifymoe", errs.StageFilter)
+	}
+
+	return nil, errs.Wrap(errs.ErrNotFound, "notifymoe", errs.StageFilter)
 }
 
 func (x *notifyMoe) query() (bool, error) {
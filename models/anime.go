@@ -142,11 +142,48 @@ type AnimeResource struct {
 }
 
 type AnimeVideo struct {
-	Source   string   `json:"Source"`
-	Referer  string   `json:"Referer,omitempty"`
-	Type     string   `json:"Type"`
-	Quality  string   `json:"Quality"`
-	Language Language `json:"Language"`
+	Source   string         `json:"Source"`
+	Referer  string         `json:"Referer,omitempty"`
+	Type     Track          `json:"Type"`
+	Quality  Quality        `json:"Quality"`
+	Audio    Language       `json:"Audio"`
+	Subtitle Language       `json:"Subtitle"`
+	Manifest *AnimeManifest `json:"Manifest,omitempty"`
+	// Streams and Subtitles are the direct links found in the player page of the source.
+	Streams   []AnimeStream `json:"Streams,omitempty"`
+	Subtitles []AnimeTrack  `json:"Subtitles,omitempty"`
+}
+
+// AnimeStream is a direct media link, Headers are needed to play it, mostly the Referer.
+type AnimeStream struct {
+	Link    string            `json:"Link"`
+	Format  string            `json:"Format"`
+	Height  int               `json:"Height,omitempty"`
+	Headers map[string]string `json:"Headers,omitempty"`
+}
+
+type AnimeRendition struct {
+	Width     int    `json:"Width"`
+	Height    int    `json:"Height"`
+	Bandwidth int    `json:"Bandwidth"`
+	Codecs    string `json:"Codecs,omitempty"`
+	Link      string `json:"Link,omitempty"`
+}
+
+type AnimeTrack struct {
+	Language string `json:"Language"`
+	Name     string `json:"Name,omitempty"`
+	Link     string `json:"Link,omitempty"`
+}
+
+// AnimeManifest is what an HLS or DASH manifest holds, Duration is in seconds.
+type AnimeManifest struct {
+	Link       string           `json:"Link"`
+	Format     string           `json:"Format"`
+	Renditions []AnimeRendition `json:"Renditions"`
+	Audio      []AnimeTrack     `json:"Audio,omitempty"`
+	Subtitles  []AnimeTrack     `json:"Subtitles,omitempty"`
+	Duration   float64          `json:"Duration"`
 }
 
 type AnimeEpisodeResources struct {
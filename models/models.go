@@ -17,10 +17,45 @@ type MetaData struct {
 	OverView string   `json:"OverView"`
 }
 
-type Video struct {
-	Link     string `json:"link"`
-	Referer  string `json:"referer"`
-	Type     string `json:"type"`
-	Quality  string `json:"quality"`
-	Language string `json:"language"`
+// Quality is the resolution of an AnimeVideo.
+type Quality string
+
+const (
+	QualityUnknown Quality = "unknown"
+	Quality360     Quality = "360p"
+	Quality480     Quality = "480p"
+	Quality720     Quality = "720p"
+	Quality1080    Quality = "1080p"
+	Quality2160    Quality = "2160p"
+)
+
+// Height returns the number of lines of the quality, zero when it is unknown.
+func (q Quality) Height() int {
+	switch q {
+	case Quality360:
+		return 360
+	case Quality480:
+		return 480
+	case Quality720:
+		return 720
+	case Quality1080:
+		return 1080
+	case Quality2160:
+		return 2160
+	}
+	return 0
 }
+
+// Track tells how an AnimeVideo is localised.
+type Track string
+
+const (
+	// TrackSub has the original audio and burned in subtitles.
+	TrackSub Track = "sub"
+	// TrackSoftSub has the original audio and subtitles that can be turned off.
+	TrackSoftSub Track = "softsub"
+	// TrackDub has a translated audio.
+	TrackDub Track = "dub"
+	// TrackRaw has the original audio and no subtitles.
+	TrackRaw Track = "raw"
+)
@@ -0,0 +1,144 @@
+package resolve
+
+import (
+	"encoding/xml"
+	"fmt"
+	"io"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+type mpd struct {
+	Duration string `xml:"mediaPresentationDuration,attr"`
+	BaseURL  string `xml:"BaseURL"`
+	Periods  []struct {
+		Duration string `xml:"duration,attr"`
+		Sets     []struct {
+			ContentType string `xml:"contentType,attr"`
+			MimeType    string `xml:"mimeType,attr"`
+			Lang        string `xml:"lang,attr"`
+			Label       string `xml:"label,attr"`
+			Codecs      string `xml:"codecs,attr"`
+			Roles       []struct {
+				Value string `xml:"value,attr"`
+			} `xml:"Role"`
+			Representations []struct {
+				ID        string `xml:"id,attr"`
+				Bandwidth int    `xml:"bandwidth,attr"`
+				Width     int    `xml:"width,attr"`
+				Height    int    `xml:"height,attr"`
+				Codecs    string `xml:"codecs,attr"`
+				MimeType  string `xml:"mimeType,attr"`
+				BaseURL   string `xml:"BaseURL"`
+			} `xml:"Representation"`
+		} `xml:"AdaptationSet"`
+	} `xml:"Period"`
+}
+
+// parseDASH reads an MPD manifest, the links it holds are resolved against base. A manifest
+// split in periods, around ads or chapters, repeats the same renditions and tracks in every
+// period, only the first of each is kept.
+func parseDASH(r io.Reader, base *url.URL) (*models.AnimeManifest, error) {
+	var doc mpd
+	if err := xml.NewDecoder(r).Decode(&doc); err != nil {
+		return nil, errs.ErrBadData
+	}
+	if doc.BaseURL != "" {
+		if u, err := url.Parse(absolute(base, doc.BaseURL)); err == nil {
+			base = u
+		}
+	}
+
+	var (
+		manifest = &models.AnimeManifest{
+			Format:   DASH,
+			Duration: isoDuration(doc.Duration),
+		}
+		seen = make(map[string]bool)
+		// first reports whether the key was not seen in an earlier period.
+		first = func(key string) bool {
+			if seen[key] {
+				return false
+			}
+			seen[key] = true
+			return true
+		}
+	)
+	for _, p := range doc.Periods {
+		if doc.Duration == "" {
+			manifest.Duration += isoDuration(p.Duration)
+		}
+		for _, set := range p.Sets {
+			kind := set.ContentType
+			if kind == "" {
+				kind = set.MimeType
+				if kind == "" && len(set.Representations) > 0 {
+					kind = set.Representations[0].MimeType
+				}
+				kind, _, _ = strings.Cut(kind, "/")
+			}
+
+			switch {
+			case kind == "audio":
+				if !first("audio/" + set.Lang + "/" + set.Label) {
+					continue
+				}
+				manifest.Audio = append(manifest.Audio, models.AnimeTrack{Language: set.Lang, Name: set.Label})
+			case kind == "text" || strings.Contains(set.MimeType, "vtt") || strings.Contains(set.MimeType, "ttml"):
+				if !first("text/" + set.Lang + "/" + set.Label) {
+					continue
+				}
+				track := models.AnimeTrack{Language: set.Lang, Name: set.Label}
+				if len(set.Representations) > 0 && set.Representations[0].BaseURL != "" {
+					track.Link = absolute(base, set.Representations[0].BaseURL)
+				}
+				manifest.Subtitles = append(manifest.Subtitles, track)
+			case kind == "video":
+				for _, v := range set.Representations {
+					rendition := models.AnimeRendition{
+						Width:     v.Width,
+						Height:    v.Height,
+						Bandwidth: v.Bandwidth,
+						Codecs:    v.Codecs,
+					}
+					if rendition.Codecs == "" {
+						rendition.Codecs = set.Codecs
+					}
+					key := fmt.Sprintf("video/%dx%d/%d/%s", v.Width, v.Height, v.Bandwidth, rendition.Codecs)
+					if !first(key) {
+						continue
+					}
+					if v.BaseURL != "" {
+						rendition.Link = absolute(base, v.BaseURL)
+					}
+					manifest.Renditions = append(manifest.Renditions, rendition)
+				}
+			}
+		}
+	}
+
+	return manifest, nil
+}
+
+var isoRgx = regexp.MustCompile(`^P(?:(\d+(?:\.\d+)?)D)?(?:T(?:(\d+(?:\.\d+)?)H)?(?:(\d+(?:\.\d+)?)M)?(?:(\d+(?:\.\d+)?)S)?)?$`)
+
+// isoDuration reads an ISO 8601 duration such as PT23M40.5S into seconds.
+func isoDuration(input string) float64 {
+	match := isoRgx.FindStringSubmatch(strings.TrimSpace(input))
+	if match == nil {
+		return 0
+	}
+
+	var total float64
+	for i, unit := range []float64{86400, 3600, 60, 1} {
+		if v, err := strconv.ParseFloat(match[i+1], 64); err == nil {
+			total += v * unit
+		}
+	}
+	return total
+}
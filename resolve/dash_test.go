@@ -0,0 +1,108 @@
+package resolve
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseDASH(t *testing.T) {
+	const input = `<?xml version="1.0" encoding="UTF-8"?>
+<MPD xmlns="urn:mpeg:dash:schema:mpd:2011" mediaPresentationDuration="PT23M40.5S">
+  <BaseURL>media/</BaseURL>
+  <Period>
+    <AdaptationSet contentType="video" codecs="avc1.640028">
+      <Representation id="v0" bandwidth="800000" width="640" height="360">
+        <BaseURL>360.mp4</BaseURL>
+      </Representation>
+      <Representation id="v1" bandwidth="4000000" width="1920" height="1080" codecs="avc1.640032">
+        <BaseURL>1080.mp4</BaseURL>
+      </Representation>
+    </AdaptationSet>
+    <AdaptationSet mimeType="audio/mp4" lang="ja" label="Japanese">
+      <Representation id="a0" bandwidth="128000"/>
+    </AdaptationSet>
+    <AdaptationSet mimeType="text/vtt" lang="en" label="English">
+      <Representation id="t0" bandwidth="500">
+        <BaseURL>en.vtt</BaseURL>
+      </Representation>
+    </AdaptationSet>
+  </Period>
+</MPD>`
+	base, _ := url.Parse("https://cdn.example.com/dash/manifest.mpd")
+
+	manifest, err := parseDASH(strings.NewReader(input), base)
+	require.NoError(t, err)
+
+	assert.Equal(t, DASH, manifest.Format)
+	assert.InDelta(t, 1420.5, manifest.Duration, 1e-9)
+	assert.Equal(t, []models.AnimeRendition{
+		{
+			Width:     640,
+			Height:    360,
+			Bandwidth: 800000,
+			Codecs:    "avc1.640028",
+			Link:      "https://cdn.example.com/dash/media/360.mp4",
+		},
+		{
+			Width:     1920,
+			Height:    1080,
+			Bandwidth: 4000000,
+			Codecs:    "avc1.640032",
+			Link:      "https://cdn.example.com/dash/media/1080.mp4",
+		},
+	}, manifest.Renditions)
+	assert.Equal(t, []models.AnimeTrack{{Language: "ja", Name: "Japanese"}}, manifest.Audio)
+	assert.Equal(t, []models.AnimeTrack{
+		{Language: "en", Name: "English", Link: "https://cdn.example.com/dash/media/en.vtt"},
+	}, manifest.Subtitles)
+}
+
+func TestParseDASHPeriods(t *testing.T) {
+	// the same renditions and tracks in every period are kept once, the durations add up
+	const period = `
+  <Period duration="PT10M">
+    <AdaptationSet contentType="video">
+      <Representation id="v0" bandwidth="800000" width="640" height="360" codecs="avc1"/>
+    </AdaptationSet>
+    <AdaptationSet contentType="audio" lang="ja"/>
+  </Period>`
+	input := `<MPD>` + period + period + `</MPD>`
+
+	manifest, err := parseDASH(strings.NewReader(input), nil)
+	require.NoError(t, err)
+
+	assert.InDelta(t, 1200, manifest.Duration, 1e-9)
+	assert.Len(t, manifest.Renditions, 1)
+	assert.Len(t, manifest.Audio, 1)
+}
+
+func TestParseDASHInvalid(t *testing.T) {
+	_, err := parseDASH(strings.NewReader("<html><body>"), nil)
+	assert.ErrorIs(t, err, errs.ErrBadData)
+}
+
+func TestISODuration(t *testing.T) {
+	for input, want := range map[string]float64{
+		"PT23M40.5S":   1420.5,
+		"PT1H":         3600,
+		"PT1H2M3S":     3723,
+		"P1DT12H":      129600,
+		" PT0.25S ":    0.25,
+		"P2D":          172800,
+		"PT":           0,
+		"":             0,
+		"23:40":        0,
+		"PT10X":        0,
+		"P1Y2M":        0,
+		"pt23m40s":     0,
+		"PT1.5H30M10S": 7210,
+	} {
+		assert.InDelta(t, want, isoDuration(input), 1e-9, input)
+	}
+}
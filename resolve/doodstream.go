@@ -0,0 +1,84 @@
+package resolve
+
+import (
+	"context"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("doodstream", []string{
+		"doodstream.com", "dood.watch", "dood.to", "dood.so", "dood.pm", "dood.wf", "dood.re", "dood.yt", "dood.li",
+		"dood.la", "dood.ws", "dood.sh", "doods.pro", "dooood.com", "d000d.com", "d0000d.com", "ds2play.com", "ds2video.com",
+	}, DoodStream))
+}
+
+var passRgx = regexp.MustCompile(`/pass_md5/[^'"]+`)
+
+const doodAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+
+// DoodStream asks the pass_md5 endpoint for the base of the link and appends the random
+// suffix and the token the player would add.
+func DoodStream(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	// the download pages /d/ have no player, the embed pages /e/ do
+	link := *embed
+	link.Path = strings.Replace(link.Path, "/d/", "/e/", 1)
+
+	body, err := page(ctx, &link, referer)
+	if err != nil {
+		return nil, err
+	}
+
+	pass := passRgx.FindString(body)
+	if pass == "" {
+		return nil, errs.ErrNotFound
+	}
+
+	endpoint, err := url.Parse(absolute(&link, pass))
+	if err != nil {
+		return nil, errs.ErrBadData
+	}
+	reply, err := client.Do(ctx, &client.Args{
+		Proxy:    true,
+		Method:   http.MethodGet,
+		Endpoint: endpoint,
+		Headers:  map[string]string{"Referer": link.String()},
+		Limit:    64 << 10,
+	})
+	if err != nil {
+		return nil, err
+	}
+	data, err := io.ReadAll(reply)
+	if err != nil {
+		return nil, err
+	}
+
+	base := strings.TrimSpace(string(data))
+	if !strings.HasPrefix(base, "http") {
+		return nil, errs.ErrNotFound
+	}
+
+	suffix := make([]byte, 10)
+	for i := range suffix {
+		suffix[i] = doodAlphabet[rand.IntN(len(doodAlphabet))]
+	}
+	stream := base + string(suffix) + "?token=" + path.Base(endpoint.Path) + "&expiry=" + strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	return &Media{
+		Streams: []Stream{{
+			Link:    stream,
+			Format:  MP4,
+			Headers: map[string]string{"Referer": origin(&link)},
+		}},
+	}, nil
+}
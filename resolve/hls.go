@@ -0,0 +1,142 @@
+package resolve
+
+import (
+	"bufio"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// playlist is a parsed HLS playlist, a master one has variants and a media one has segments.
+type playlist struct {
+	master   bool
+	variants []models.AnimeRendition
+	audio    []models.AnimeTrack
+	subs     []models.AnimeTrack
+	duration float64
+	ended    bool
+}
+
+// parseHLS reads an m3u8 playlist, the links it holds are resolved against base.
+func parseHLS(r io.Reader, base *url.URL) (*playlist, error) {
+	var (
+		list    = new(playlist)
+		scanner = bufio.NewScanner(r)
+		pending *models.AnimeRendition
+		first   = true
+	)
+	scanner.Buffer(make([]byte, 64<<10), 4<<20)
+
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if first {
+			if !strings.HasPrefix(line, "#EXTM3U") {
+				return nil, errs.ErrBadData
+			}
+			first = false
+			continue
+		}
+
+		tag, value, _ := strings.Cut(line, ":")
+		switch tag {
+		case "#EXT-X-STREAM-INF":
+			list.master = true
+			attrs := attributes(value)
+			pending = &models.AnimeRendition{
+				Codecs: attrs["CODECS"],
+			}
+			pending.Bandwidth, _ = strconv.Atoi(attrs["BANDWIDTH"])
+			if v, err := strconv.Atoi(attrs["AVERAGE-BANDWIDTH"]); err == nil && v > 0 {
+				pending.Bandwidth = v
+			}
+			if w, h, ok := strings.Cut(attrs["RESOLUTION"], "x"); ok {
+				pending.Width, _ = strconv.Atoi(w)
+				pending.Height, _ = strconv.Atoi(h)
+			}
+		case "#EXT-X-MEDIA":
+			list.master = true
+			attrs := attributes(value)
+			track := models.AnimeTrack{
+				Language: attrs["LANGUAGE"],
+				Name:     attrs["NAME"],
+			}
+			if attrs["URI"] != "" {
+				track.Link = absolute(base, attrs["URI"])
+			}
+			switch attrs["TYPE"] {
+			case "AUDIO":
+				list.audio = append(list.audio, track)
+			case "SUBTITLES", "CLOSED-CAPTIONS":
+				list.subs = append(list.subs, track)
+			}
+		case "#EXTINF":
+			n, _, _ := strings.Cut(value, ",")
+			if d, err := strconv.ParseFloat(strings.TrimSpace(n), 64); err == nil {
+				list.duration += d
+			}
+		case "#EXT-X-ENDLIST":
+			list.ended = true
+		default:
+			if strings.HasPrefix(line, "#") {
+				continue
+			}
+			if pending != nil {
+				pending.Link = absolute(base, line)
+				list.variants = append(list.variants, *pending)
+				pending = nil
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if first {
+		return nil, errs.ErrBadData
+	}
+
+	return list, nil
+}
+
+// attributes splits an HLS attribute list such as BANDWIDTH=800000,CODECS="avc1.4d401f,mp4a.40.2".
+func attributes(input string) map[string]string {
+	var (
+		attrs  = make(map[string]string)
+		key    strings.Builder
+		value  strings.Builder
+		inKey  = true
+		quoted bool
+	)
+	flush := func() {
+		if k := strings.TrimSpace(key.String()); k != "" {
+			attrs[strings.ToUpper(k)] = value.String()
+		}
+		key.Reset()
+		value.Reset()
+		inKey = true
+	}
+
+	for _, c := range input {
+		switch {
+		case inKey && c == '=':
+			inKey = false
+		case inKey:
+			key.WriteRune(c)
+		case c == '"':
+			quoted = !quoted
+		case c == ',' && !quoted:
+			flush()
+		default:
+			value.WriteRune(c)
+		}
+	}
+	flush()
+
+	return attrs
+}
@@ -0,0 +1,99 @@
+package resolve
+
+import (
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseHLSMaster(t *testing.T) {
+	const input = `#EXTM3U
+#EXT-X-VERSION:3
+#EXT-X-MEDIA:TYPE=AUDIO,GROUP-ID="aud",LANGUAGE="ja",NAME="Japanese",URI="audio/ja.m3u8"
+#EXT-X-MEDIA:TYPE=SUBTITLES,GROUP-ID="sub",LANGUAGE="en",NAME="English",URI="https://subs.example.com/en.m3u8"
+#EXT-X-STREAM-INF:BANDWIDTH=800000,RESOLUTION=640x360,CODECS="avc1.4d401e,mp4a.40.2"
+360/index.m3u8
+
+#EXT-X-STREAM-INF:BANDWIDTH=5000000,AVERAGE-BANDWIDTH=4200000,RESOLUTION=1920x1080,CODECS="avc1.640028,mp4a.40.2"
+/hls/1080/index.m3u8
+`
+	base, _ := url.Parse("https://cdn.example.com/hls/master.m3u8")
+
+	list, err := parseHLS(strings.NewReader(input), base)
+	require.NoError(t, err)
+
+	assert.True(t, list.master)
+	assert.Equal(t, []models.AnimeRendition{
+		{
+			Width:     640,
+			Height:    360,
+			Bandwidth: 800000,
+			Codecs:    "avc1.4d401e,mp4a.40.2",
+			Link:      "https://cdn.example.com/hls/360/index.m3u8",
+		},
+		{
+			Width:     1920,
+			Height:    1080,
+			Bandwidth: 4200000,
+			Codecs:    "avc1.640028,mp4a.40.2",
+			Link:      "https://cdn.example.com/hls/1080/index.m3u8",
+		},
+	}, list.variants)
+	assert.Equal(t, []models.AnimeTrack{
+		{Language: "ja", Name: "Japanese", Link: "https://cdn.example.com/hls/audio/ja.m3u8"},
+	}, list.audio)
+	assert.Equal(t, []models.AnimeTrack{
+		{Language: "en", Name: "English", Link: "https://subs.example.com/en.m3u8"},
+	}, list.subs)
+}
+
+func TestParseHLSMedia(t *testing.T) {
+	const input = `#EXTM3U
+#EXT-X-TARGETDURATION:10
+#EXTINF:10.0,
+seg0.ts
+#EXTINF:9.5,
+seg1.ts
+#EXTINF:4.25,title
+seg2.ts
+#EXT-X-ENDLIST
+`
+	list, err := parseHLS(strings.NewReader(input), nil)
+	require.NoError(t, err)
+
+	assert.False(t, list.master)
+	assert.Empty(t, list.variants)
+	assert.InDelta(t, 23.75, list.duration, 1e-9)
+	assert.True(t, list.ended)
+}
+
+func TestParseHLSInvalid(t *testing.T) {
+	for name, input := range map[string]string{
+		"empty":  "",
+		"blank":  "\n\n  \n",
+		"html":   "<html><body>404</body></html>",
+		"header": "#EXT-X-VERSION:3\n#EXTM3U\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			_, err := parseHLS(strings.NewReader(input), nil)
+			assert.ErrorIs(t, err, errs.ErrBadData)
+		})
+	}
+}
+
+func TestAttributes(t *testing.T) {
+	got := attributes(`BANDWIDTH=800000,codecs="avc1.4d401f,mp4a.40.2",NAME="A, B",RESOLUTION=1280x720`)
+	assert.Equal(t, map[string]string{
+		"BANDWIDTH":  "800000",
+		"CODECS":     "avc1.4d401f,mp4a.40.2",
+		"NAME":       "A, B",
+		"RESOLUTION": "1280x720",
+	}, got)
+
+	assert.Empty(t, attributes(""))
+}
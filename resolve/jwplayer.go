@@ -0,0 +1,93 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("jwplayer", []string{
+		"streamwish.com", "streamwish.to", "embedwish.com", "wishfast.top", "awish.pro", "dwish.pro", "swdyu.com",
+		"filelions.com", "filelions.to", "alions.pro", "vidhide.com", "vidhidepro.com", "vidhidevip.com", "streamhide.to",
+		"filemoon.sx", "filemoon.to", "filemoon.in", "vidmoly.to", "vidmoly.me", "uqload.co", "uqload.io", "uqload.ws",
+		"yourupload.com", "vidguard.to",
+	}, JWPlayer))
+}
+
+var (
+	objectRgx  = regexp.MustCompile(`\{[^{}]*?\bfile\s*:\s*["']([^"']+)["'][^{}]*\}`)
+	labelRgx   = regexp.MustCompile(`\blabel\s*:\s*["']([^"']*)["']`)
+	kindRgx    = regexp.MustCompile(`\bkind\s*:\s*["']([^"']*)["']`)
+	hlsLinkRgx = regexp.MustCompile(`["']hls\d?["']\s*:\s*["']([^"']+)["']`)
+	sourcesRgx = regexp.MustCompile(`sources\s*:\s*\[\s*["']([^"']+)["']`)
+)
+
+// JWPlayer extracts the sources and the caption tracks of the hosts built on JW Player,
+// their setup is often hidden in a packed script.
+func JWPlayer(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	body, err := page(ctx, embed, referer)
+	if err != nil {
+		return nil, err
+	}
+	body = unpack(body)
+
+	var (
+		media   = new(Media)
+		seen    = make(map[string]bool)
+		headers = map[string]string{"Referer": origin(embed)}
+	)
+	add := func(link, label string) {
+		link = absolute(embed, link)
+		if seen[link] {
+			return
+		}
+		seen[link] = true
+		media.Streams = append(media.Streams, Stream{
+			Link:    link,
+			Format:  format(link),
+			Height:  height(label),
+			Headers: headers,
+		})
+	}
+
+	for _, v := range objectRgx.FindAllStringSubmatch(body, -1) {
+		var (
+			file  = v[1]
+			label string
+			kind  string
+		)
+		if m := labelRgx.FindStringSubmatch(v[0]); m != nil {
+			label = m[1]
+		}
+		if m := kindRgx.FindStringSubmatch(v[0]); m != nil {
+			kind = strings.ToLower(m[1])
+		}
+
+		lower := strings.ToLower(file)
+		switch {
+		case kind == "captions" || kind == "subtitles" || strings.HasSuffix(lower, ".vtt") || strings.HasSuffix(lower, ".srt"):
+			media.Subtitles = append(media.Subtitles, Subtitle{
+				Link:  absolute(embed, file),
+				Label: label,
+			})
+		case kind == "thumbnails" || strings.HasSuffix(lower, ".jpg") || strings.HasSuffix(lower, ".png"):
+		default:
+			add(file, label)
+		}
+	}
+	for _, v := range hlsLinkRgx.FindAllStringSubmatch(body, -1) {
+		add(v[1], "")
+	}
+	for _, v := range sourcesRgx.FindAllStringSubmatch(body, -1) {
+		add(v[1], v[1])
+	}
+
+	if len(media.Streams) == 0 {
+		return nil, errs.ErrNotFound
+	}
+	return media, nil
+}
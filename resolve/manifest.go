@@ -0,0 +1,110 @@
+package resolve
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"slices"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// manifests are text files, anything bigger is not one.
+const manifestLimit = 8 << 20
+
+// Inspect downloads the HLS or DASH manifest and returns its renditions sorted from the best,
+// its audio and subtitle tracks and its duration. For an HLS master playlist the duration is
+// read from the playlist of the first variant.
+func Inspect(ctx context.Context, link string, headers map[string]string) (*models.AnimeManifest, error) {
+	endpoint, err := url.Parse(link)
+	if err != nil {
+		return nil, errs.ErrBadData
+	}
+
+	data, err := manifest(ctx, endpoint, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var result *models.AnimeManifest
+	if bytes.Contains(data[:min(len(data), 1024)], []byte("<MPD")) {
+		result, err = parseDASH(bytes.NewReader(data), endpoint)
+		if err != nil {
+			return nil, err
+		}
+	} else {
+		list, err := parseHLS(bytes.NewReader(data), endpoint)
+		if err != nil {
+			return nil, err
+		}
+		result = &models.AnimeManifest{
+			Format:     HLS,
+			Renditions: list.variants,
+			Audio:      list.audio,
+			Subtitles:  list.subs,
+			Duration:   list.duration,
+		}
+		if list.master && len(list.variants) > 0 {
+			result.Duration = duration(ctx, list.variants[0].Link, headers)
+		}
+	}
+
+	result.Link = link
+	slices.SortStableFunc(result.Renditions, func(a, b models.AnimeRendition) int {
+		if a.Height != b.Height {
+			return b.Height - a.Height
+		}
+		return b.Bandwidth - a.Bandwidth
+	})
+	return result, nil
+}
+
+func manifest(ctx context.Context, endpoint *url.URL, headers map[string]string) ([]byte, error) {
+	body, err := client.Do(ctx, &client.Args{
+		Proxy:    true,
+		Method:   http.MethodGet,
+		Endpoint: endpoint,
+		Headers:  headers,
+		Limit:    manifestLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return io.ReadAll(body)
+}
+
+// duration sums the segments of an HLS media playlist, zero when it cannot be read.
+func duration(ctx context.Context, link string, headers map[string]string) float64 {
+	endpoint, err := url.Parse(link)
+	if err != nil {
+		return 0
+	}
+
+	data, err := manifest(ctx, endpoint, headers)
+	if err != nil {
+		logger.Warn("cannot get the media playlist", "link", link, "error", err)
+		return 0
+	}
+	list, err := parseHLS(bytes.NewReader(data), endpoint)
+	if err != nil {
+		return 0
+	}
+	return list.duration
+}
+
+// MatchesRuntime reports whether the duration of the manifest is close to the runtime of the
+// episode in minutes, within two minutes or a tenth of the runtime. An unknown duration or
+// runtime matches.
+func MatchesRuntime(manifest *models.AnimeManifest, runtime int) bool {
+	if manifest == nil || manifest.Duration <= 0 || runtime <= 0 {
+		return true
+	}
+
+	expected := float64(runtime) * 60
+	return math.Abs(manifest.Duration-expected) <= max(120, expected/10)
+}
@@ -0,0 +1,51 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("mixdrop", []string{"mixdrop.co", "mixdrop.to", "mixdrop.ag", "mixdrop.ps", "mixdrop.sx", "mixdrop.bz", "mixdrp.co", "mixdrp.to", "m1xdrop.com"}, MixDrop))
+}
+
+var (
+	mixdropRgx    = regexp.MustCompile(`MDCore\.wurl\s*=\s*["']([^"']+)["']`)
+	mixdropSubRgx = regexp.MustCompile(`MDCore\.remotesub\s*=\s*["']([^"']+)["']`)
+)
+
+// MixDrop reads the mp4 link and the remote subtitle from the packed player setup.
+func MixDrop(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	// the file pages /f/ only link to the player, the embed pages /e/ hold it
+	link := *embed
+	link.Path = strings.Replace(link.Path, "/f/", "/e/", 1)
+
+	body, err := page(ctx, &link, referer)
+	if err != nil {
+		return nil, err
+	}
+	body = unpack(body)
+
+	match := mixdropRgx.FindStringSubmatch(body)
+	if match == nil {
+		return nil, errs.ErrNotFound
+	}
+
+	media := &Media{
+		Streams: []Stream{{
+			Link:    absolute(&link, match[1]),
+			Format:  MP4,
+			Headers: map[string]string{"Referer": origin(&link)},
+		}},
+	}
+	if sub := mixdropSubRgx.FindStringSubmatch(body); sub != nil {
+		if v, err := url.QueryUnescape(sub[1]); err == nil {
+			media.Subtitles = append(media.Subtitles, Subtitle{Link: absolute(&link, v)})
+		}
+	}
+	return media, nil
+}
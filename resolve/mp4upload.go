@@ -0,0 +1,38 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("mp4upload", []string{"mp4upload.com"}, Mp4Upload))
+}
+
+var mp4uploadRgx = regexp.MustCompile(`src\s*:\s*["']([^"']+\.mp4[^"']*)["']`)
+
+// Mp4Upload extracts the mp4 file of the video.js player, it only plays with the host as referer.
+func Mp4Upload(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	body, err := page(ctx, embed, referer)
+	if err != nil {
+		return nil, err
+	}
+
+	match := mp4uploadRgx.FindStringSubmatch(unpack(body))
+	if match == nil {
+		return nil, errs.ErrNotFound
+	}
+
+	link := absolute(embed, match[1])
+	return &Media{
+		Streams: []Stream{{
+			Link:    link,
+			Format:  MP4,
+			Height:  height(link),
+			Headers: map[string]string{"Referer": "https://www.mp4upload.com/"},
+		}},
+	}, nil
+}
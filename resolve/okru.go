@@ -0,0 +1,95 @@
+package resolve
+
+import (
+	"context"
+	"encoding/json"
+	"net/url"
+	"strings"
+
+	"github.com/PuerkitoBio/goquery"
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("okru", []string{"ok.ru", "odnoklassniki.ru"}, OkRu))
+}
+
+// the names ok.ru gives to its renditions.
+var okruHeights = map[string]int{
+	"mobile": 144,
+	"lowest": 240,
+	"low":    360,
+	"sd":     480,
+	"hd":     720,
+	"full":   1080,
+	"quad":   1440,
+	"ultra":  2160,
+}
+
+type okruOptions struct {
+	Flashvars struct {
+		Metadata string `json:"metadata"`
+	} `json:"flashvars"`
+}
+
+type okruMetadata struct {
+	Videos []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"videos"`
+	HlsManifestURL string `json:"hlsManifestUrl"`
+}
+
+// OkRu reads the renditions from the player options embedded in the page.
+func OkRu(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	body, err := page(ctx, embed, referer)
+	if err != nil {
+		return nil, err
+	}
+
+	doc, err := goquery.NewDocumentFromReader(strings.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	data, ok := doc.Find("[data-options]").First().Attr("data-options")
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+
+	var options okruOptions
+	if err = json.Unmarshal([]byte(data), &options); err != nil {
+		return nil, errs.ErrBadData
+	}
+	var metadata okruMetadata
+	if err = json.Unmarshal([]byte(options.Flashvars.Metadata), &metadata); err != nil {
+		return nil, errs.ErrBadData
+	}
+
+	var (
+		media   = new(Media)
+		headers = map[string]string{"Referer": origin(embed)}
+	)
+	for _, v := range metadata.Videos {
+		if v.URL == "" {
+			continue
+		}
+		media.Streams = append(media.Streams, Stream{
+			Link:    absolute(embed, v.URL),
+			Format:  MP4,
+			Height:  okruHeights[v.Name],
+			Headers: headers,
+		})
+	}
+	if metadata.HlsManifestURL != "" {
+		media.Streams = append(media.Streams, Stream{
+			Link:    absolute(embed, metadata.HlsManifestURL),
+			Format:  HLS,
+			Headers: headers,
+		})
+	}
+
+	if len(media.Streams) == 0 {
+		return nil, errs.ErrNotFound
+	}
+	return media, nil
+}
@@ -0,0 +1,86 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anicine/anicine-scraper/client"
+)
+
+// Extractor turns the player page of an embed host into direct stream urls.
+type Extractor interface {
+	Name() string
+	Hosts() []string
+	Extract(ctx context.Context, embed *url.URL, referer string) (*Media, error)
+}
+
+// ExtractFunc is the signature shared by every extractor of this package.
+type ExtractFunc func(ctx context.Context, embed *url.URL, referer string) (*Media, error)
+
+type extractor struct {
+	name  string
+	hosts []string
+	fn    ExtractFunc
+}
+
+func (x *extractor) Name() string {
+	return x.name
+}
+
+func (x *extractor) Hosts() []string {
+	return x.hosts
+}
+
+func (x *extractor) Extract(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	return x.fn(ctx, embed, referer)
+}
+
+// NewExtractor wraps an extract function into an Extractor that can be registered.
+// The hosts cover their sub domains.
+func NewExtractor(name string, hosts []string, fn ExtractFunc) Extractor {
+	x := &extractor{
+		name: strings.ToLower(name),
+		fn:   fn,
+	}
+	for _, v := range hosts {
+		x.hosts = append(x.hosts, strings.ToLower(v))
+	}
+
+	return x
+}
+
+var (
+	registry = make(map[string]Extractor)
+	rmutex   sync.RWMutex
+)
+
+// Register adds the extractor for every one of its hosts, replacing the previous one. The
+// hosts are kept out of the response cache since their pages hold links that expire.
+func Register(e Extractor) {
+	if e == nil {
+		return
+	}
+
+	rmutex.Lock()
+	defer rmutex.Unlock()
+	for _, v := range e.Hosts() {
+		registry[v] = e
+	}
+	client.Volatile(e.Hosts()...)
+}
+
+// Lookup returns the extractor of the host, walking up the parent domains until one is found.
+func Lookup(host string) (Extractor, bool) {
+	rmutex.RLock()
+	defer rmutex.RUnlock()
+
+	for h := strings.TrimPrefix(strings.ToLower(host), "www."); h != ""; {
+		if e, ok := registry[h]; ok {
+			return e, true
+		}
+		_, h, _ = strings.Cut(h, ".")
+	}
+	return nil, false
+}
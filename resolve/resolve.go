@@ -0,0 +1,119 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+// Resolve fetches the player page of the video with the extractor of its host and returns the
+// direct streams and the subtitles. It returns errs.ErrNotFound for hosts without an extractor.
+func Resolve(ctx context.Context, video models.AnimeVideo) (*Media, error) {
+	source := strings.TrimSpace(video.Source)
+	if strings.HasPrefix(source, "//") {
+		source = "https:" + source
+	}
+	embed, err := url.Parse(source)
+	if err != nil || embed.Host == "" {
+		return nil, errs.ErrBadData
+	}
+
+	extractor, ok := Lookup(embed.Hostname())
+	if !ok {
+		return nil, errs.ErrNotFound
+	}
+
+	media, err := extractor.Extract(ctx, embed, video.Referer)
+	if err != nil {
+		return nil, errs.Wrap(err, extractor.Name(), errs.StageScrape)
+	}
+	for i, v := range media.Streams {
+		if v.Format != HLS && v.Format != DASH {
+			continue
+		}
+		manifest, err := Inspect(ctx, v.Link, v.Headers)
+		if err != nil {
+			logger.Warn("cannot inspect the manifest", "link", v.Link, "error", err)
+			continue
+		}
+		media.Streams[i].Manifest = manifest
+		if len(manifest.Renditions) > 0 {
+			media.Streams[i].Height = max(v.Height, manifest.Renditions[0].Height)
+		}
+	}
+
+	logger.Info("resolved embed", "host", embed.Host, "streams", len(media.Streams), "subtitles", len(media.Subtitles), "height", media.Height())
+	return media, nil
+}
+
+// Update resolves every video of the result with at most parallel requests in flight, default is 8,
+// and sets its quality to the best resolution that was found, its manifest to the one of the best
+// stream and attaches its streams and subtitles. Videos that cannot be resolved keep the quality
+// the scraper guessed. The media are returned by source.
+func Update(ctx context.Context, result *scrape.Result, parallel int) map[string]*Media {
+	if parallel <= 0 {
+		parallel = 8
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mutex sync.Mutex
+		slots = make(chan struct{}, parallel)
+		found = make(map[string]*Media)
+	)
+	resolve := func(video *models.AnimeVideo) {
+		defer wg.Done()
+		defer func() { <-slots }()
+
+		media, err := Resolve(ctx, *video)
+		if err != nil {
+			return
+		}
+		if height := media.Height(); height > 0 {
+			video.Quality = analyze.CleanHeight(height)
+		}
+		video.Manifest = media.Manifest()
+		media.Attach(video)
+
+		mutex.Lock()
+		found[video.Source] = media
+		mutex.Unlock()
+	}
+
+	for _, ep := range result.Episodes {
+		for _, list := range [][]scrape.SourceVideo{ep.Videos, ep.Download} {
+			for i := range list {
+				if _, ok := Lookup(host(list[i].Video.Source)); !ok {
+					continue
+				}
+				select {
+				case slots <- struct{}{}:
+				case <-ctx.Done():
+					wg.Wait()
+					return found
+				}
+				wg.Add(1)
+				go resolve(&list[i].Video)
+			}
+		}
+	}
+	wg.Wait()
+
+	return found
+}
+
+func host(source string) string {
+	if strings.HasPrefix(source, "//") {
+		source = "https:" + source
+	}
+	if u, err := url.Parse(source); err == nil {
+		return u.Hostname()
+	}
+	return ""
+}
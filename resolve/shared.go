@@ -0,0 +1,166 @@
+package resolve
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+var logger = slog.Default().WithGroup("[RESOLVE]")
+
+// Stream formats.
+const (
+	HLS  = "hls"
+	DASH = "dash"
+	MP4  = "mp4"
+)
+
+// Stream is a direct media url, Headers are needed to play it, mostly the Referer.
+type Stream struct {
+	Link    string            `json:"Link"`
+	Format  string            `json:"Format"`
+	Height  int               `json:"Height,omitempty"`
+	Headers map[string]string `json:"Headers,omitempty"`
+	// Manifest is filled for HLS and DASH streams whose manifest could be read.
+	Manifest *models.AnimeManifest `json:"Manifest,omitempty"`
+}
+
+// Subtitle is an external subtitle track of a stream.
+type Subtitle struct {
+	Link     string `json:"Link"`
+	Label    string `json:"Label,omitempty"`
+	Language string `json:"Language,omitempty"`
+}
+
+// Media is everything an extractor found in a player page.
+type Media struct {
+	Streams   []Stream   `json:"Streams"`
+	Subtitles []Subtitle `json:"Subtitles,omitempty"`
+}
+
+// Height returns the highest resolution of the streams, zero when none is known.
+func (x *Media) Height() int {
+	height := 0
+	for _, v := range x.Streams {
+		height = max(height, v.Height)
+	}
+	return height
+}
+
+// Manifest returns the manifest of the best HLS or DASH stream, nil when none was read.
+func (x *Media) Manifest() *models.AnimeManifest {
+	var best *Stream
+	for i, v := range x.Streams {
+		if v.Manifest != nil && (best == nil || v.Height > best.Height) {
+			best = &x.Streams[i]
+		}
+	}
+	if best == nil {
+		return nil
+	}
+	return best.Manifest
+}
+
+// Attach copies the streams and the subtitles into the video.
+func (x *Media) Attach(video *models.AnimeVideo) {
+	video.Streams = make([]models.AnimeStream, 0, len(x.Streams))
+	for _, v := range x.Streams {
+		video.Streams = append(video.Streams, models.AnimeStream{
+			Link:    v.Link,
+			Format:  v.Format,
+			Height:  v.Height,
+			Headers: v.Headers,
+		})
+	}
+
+	video.Subtitles = nil
+	for _, v := range x.Subtitles {
+		video.Subtitles = append(video.Subtitles, models.AnimeTrack{
+			Language: v.Language,
+			Name:     v.Label,
+			Link:     v.Link,
+		})
+	}
+}
+
+var (
+	heightRgx = regexp.MustCompile(`(?i)(\d{3,4})p`)
+)
+
+// page downloads the player page with the referer the embed expects.
+func page(ctx context.Context, link *url.URL, referer string) (string, error) {
+	headers := map[string]string{
+		"Accept": "text/html,application/xhtml+xml,*/*",
+	}
+	if referer != "" {
+		headers["Referer"] = referer
+	}
+
+	body, err := client.Do(ctx, &client.Args{
+		Proxy:    true,
+		Method:   http.MethodGet,
+		Endpoint: link,
+		Headers:  headers,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	data, err := io.ReadAll(body)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// format guesses the stream format from its url.
+func format(link string) string {
+	path := strings.ToLower(link)
+	if u, err := url.Parse(link); err == nil {
+		path = strings.ToLower(u.Path)
+	}
+	switch {
+	case strings.Contains(path, ".m3u8"):
+		return HLS
+	case strings.Contains(path, ".mpd"):
+		return DASH
+	default:
+		return MP4
+	}
+}
+
+// height reads a resolution such as 1080p from a label or a file name.
+func height(label string) int {
+	match := heightRgx.FindStringSubmatch(label)
+	if len(match) < 2 {
+		return 0
+	}
+	n, _ := strconv.Atoi(match[1])
+	return n
+}
+
+// absolute resolves a link found in the page against the page url.
+func absolute(base *url.URL, link string) string {
+	link = strings.ReplaceAll(strings.TrimSpace(link), `\/`, "/")
+	ref, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	if base == nil {
+		return ref.String()
+	}
+	return base.ResolveReference(ref).String()
+}
+
+// origin returns the scheme and the host of the link with a trailing slash.
+func origin(link *url.URL) string {
+	return link.Scheme + "://" + link.Host + "/"
+}
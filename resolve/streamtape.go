@@ -0,0 +1,55 @@
+package resolve
+
+import (
+	"context"
+	"net/url"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+func init() {
+	Register(NewExtractor("streamtape", []string{"streamtape.com", "streamtape.net", "streamtape.to", "strtape.cloud", "streamta.pe", "strcloud.link"}, StreamTape))
+}
+
+// the link is split in two and the second half is cut to fool scrapers:
+// document.getElementById('robotlink').innerHTML = '//streamtape.com/get_video?id=' + ('xcdb...token=abc').substring(1).substring(2);
+var streamtapeRgx = regexp.MustCompile(`getElementById\('(?:no)?robotlink'\)\.innerHTML\s*=\s*['"]([^'"]+)['"]\s*\+\s*\(?['"]([^'"]+)['"]\)?((?:\.substring\(\d+\))*)`)
+
+var substringRgx = regexp.MustCompile(`\.substring\((\d+)\)`)
+
+// StreamTape rebuilds the get_video link hidden in the page, it redirects to the mp4 file.
+func StreamTape(ctx context.Context, embed *url.URL, referer string) (*Media, error) {
+	body, err := page(ctx, embed, referer)
+	if err != nil {
+		return nil, err
+	}
+
+	match := streamtapeRgx.FindStringSubmatch(body)
+	if match == nil {
+		return nil, errs.ErrNotFound
+	}
+
+	tail := match[2]
+	for _, v := range substringRgx.FindAllStringSubmatch(match[3], -1) {
+		n, _ := strconv.Atoi(v[1])
+		if n > len(tail) {
+			return nil, errs.ErrBadData
+		}
+		tail = tail[n:]
+	}
+
+	link := strings.TrimSpace(match[1] + tail)
+	if strings.HasPrefix(link, "//") {
+		link = "https:" + link
+	}
+	return &Media{
+		Streams: []Stream{{
+			Link:    link + "&stream=1",
+			Format:  MP4,
+			Headers: map[string]string{"Referer": origin(embed)},
+		}},
+	}, nil
+}
@@ -0,0 +1,58 @@
+package resolve
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+const packAlphabet = "0123456789abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+var (
+	packedRgx = regexp.MustCompile(`}\('((?:\\.|[^'\\])*)',\s*(\d+),\s*(\d+),\s*'((?:\\.|[^'\\])*)'\.split\('\|'\)`)
+	wordRgx   = regexp.MustCompile(`\b\w+\b`)
+)
+
+// unpack decodes every p,a,c,k,e,d packed script of the page and appends them to it,
+// so the sources they hide can be matched like plain ones.
+func unpack(page string) string {
+	if !strings.Contains(page, "p,a,c,k,e,") {
+		return page
+	}
+
+	var out strings.Builder
+	out.WriteString(page)
+	for _, v := range packedRgx.FindAllStringSubmatch(page, -1) {
+		base, err := strconv.Atoi(v[2])
+		if err != nil || base < 2 || base > len(packAlphabet) {
+			continue
+		}
+		var (
+			payload = strings.NewReplacer(`\'`, `'`, `\\`, `\`).Replace(v[1])
+			words   = strings.Split(v[4], "|")
+		)
+
+		out.WriteString("\n")
+		out.WriteString(wordRgx.ReplaceAllStringFunc(payload, func(word string) string {
+			n := decode(word, base)
+			if n < 0 || n >= len(words) || words[n] == "" {
+				return word
+			}
+			return words[n]
+		}))
+	}
+	return out.String()
+}
+
+// decode reads the word as a number in the base of the packer, or returns -1.
+func decode(word string, base int) int {
+	n := 0
+	for _, c := range word {
+		i := strings.IndexRune(packAlphabet[:base], c)
+		if i < 0 {
+			return -1
+		}
+		n = n*base + i
+	}
+	return n
+}
@@ -0,0 +1,34 @@
+package resolve
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUnpack(t *testing.T) {
+	const packed = `<script>eval(function(p,a,c,k,e,d){while(c--)if(k[c])p=p.replace(new RegExp('\\b'+c.toString(a)+'\\b','g'),k[c]);return p}('0 1=\'2://3.4/5.6\';a.b(1)',36,12,'var|file|https|cdn|example|video|m3u8||||player|load'.split('|'),0,{}))</script>`
+
+	got := unpack(packed)
+	assert.Contains(t, got, packed, "the page is kept")
+	assert.Contains(t, got, `var file='https://cdn.example/video.m3u8';player.load(file)`)
+}
+
+func TestUnpackPlain(t *testing.T) {
+	const page = `<script>var file="https://cdn.example/video.mp4"</script>`
+	assert.Equal(t, page, unpack(page))
+}
+
+func TestUnpackBadBase(t *testing.T) {
+	const page = `eval(function(p,a,c,k,e,d){}('0',99,1,'var'.split('|'),0,{}))`
+	assert.Equal(t, page, unpack(page))
+}
+
+func TestDecode(t *testing.T) {
+	assert.Equal(t, 0, decode("0", 10))
+	assert.Equal(t, 10, decode("a", 36))
+	assert.Equal(t, 36, decode("10", 36))
+	assert.Equal(t, 61, decode("Z", 62))
+	assert.Equal(t, -1, decode("a", 10))
+	assert.Equal(t, -1, decode("_", 62))
+}
@@ -0,0 +1,388 @@
+package anilist
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+const attempts = 5
+
+// wait returns how long the API asks to wait, from Retry-After or from the time the
+// window resets, a minute when it says neither.
+func wait(header http.Header) time.Duration {
+	if secs, err := strconv.Atoi(header.Get("Retry-After")); err == nil {
+		return time.Duration(secs+1) * time.Second
+	}
+	if reset, err := strconv.ParseInt(header.Get("X-RateLimit-Reset"), 10, 64); err == nil {
+		if d := time.Until(time.Unix(reset, 0)); d > 0 {
+			return d + time.Second
+		}
+	}
+	return time.Minute
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return context.Canceled
+	case <-timer.C:
+		return nil
+	}
+}
+
+// pace holds back the next requests to the API once the window is spent, so they wait
+// for the reset instead of being refused.
+func pace(header http.Header) {
+	remaining, err := strconv.Atoi(header.Get("X-RateLimit-Remaining"))
+	if err != nil || remaining > 1 {
+		return
+	}
+
+	d := wait(header)
+	logger.Warn("rate limit is reached", "remaining", remaining, "wait", d)
+	client.Pause(host, d)
+}
+
+// post sends the query and decodes the media it returns. Refused requests are paused for
+// the time the API asks and sent again.
+func post(ctx context.Context, variables map[string]int) (*anilistMedia, error) {
+	payload, err := json.Marshal(&anilistRequest{
+		Query:     mediaQuery,
+		Variables: variables,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for i := 0; i < attempts; i++ {
+		reply, err := client.Probe(ctx, &client.Args{
+			Proxy:  true,
+			Method: http.MethodPost,
+			Headers: map[string]string{
+				"Content-Type": "application/json",
+				"Accept":       "application/json",
+			},
+			Endpoint: &url.URL{
+				Scheme: "https",
+				Host:   host,
+				Path:   "/",
+			},
+			Body: bytes.NewReader(payload),
+		})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			logger.Warn("retry to query anilist", "error", err)
+			if err = sleep(ctx, time.Duration(i+1)*time.Second); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		pace(reply.Header)
+
+		switch {
+		case reply.Status == http.StatusTooManyRequests:
+			d := wait(reply.Header)
+			logger.Warn("request was refused", "wait", d)
+			client.Pause(host, d)
+			continue
+		case reply.Status >= http.StatusInternalServerError:
+			if err = sleep(ctx, time.Duration(i+1)*time.Second); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		var data anilistResponse
+		if err = json.Unmarshal(reply.Body, &data); err != nil {
+			logger.Error("cannot decode JSON data", "code", reply.Status, "error", err)
+			return nil, errs.HTTP(host, reply.Status, errs.ErrBadData)
+		}
+		for _, v := range data.Errors {
+			if v.Status == http.StatusNotFound {
+				return nil, errs.HTTP(host, v.Status, errs.ErrNotFound)
+			}
+			logger.Error("query was rejected", "code", v.Status, "message", v.Message)
+		}
+		if data.Data.Media == nil {
+			return nil, errs.HTTP(host, reply.Status, errs.ErrNoData)
+		}
+
+		return data.Data.Media, nil
+	}
+
+	return nil, errs.HTTP(host, http.StatusTooManyRequests, errs.ErrNoData)
+}
+
+// Anime returns the anime by its anilist id.
+func Anime(ctx context.Context, id int) (*models.Anime, error) {
+	return fetch(ctx, "id", id)
+}
+
+// Mal returns the anime by its MyAnimeList id.
+func Mal(ctx context.Context, id int) (*models.Anime, error) {
+	return fetch(ctx, "idMal", id)
+}
+
+// fetch returns the anime and walks the pages of its characters.
+func fetch(ctx context.Context, field string, id int) (*models.Anime, error) {
+	media, err := post(ctx, map[string]int{field: id, "page": 1})
+	if err != nil {
+		return nil, errs.Wrap(err, "anilist", errs.StageFetch)
+	}
+
+	characters := media.Characters.Edges
+	for page := 2; media.Characters.PageInfo.HasNextPage && page <= maxPages; page++ {
+		next, err := post(ctx, map[string]int{"id": media.ID, "page": page})
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, errs.Wrap(err, "anilist", errs.StageFetch)
+			}
+			logger.Warn("cannot get the characters", "AniList", media.ID, "page", page, "error", err)
+			break
+		}
+		characters = append(characters, next.Characters.Edges...)
+		media.Characters.PageInfo = next.Characters.PageInfo
+	}
+	media.Characters.Edges = characters
+
+	anime := convert(media)
+	logger.Info("anime was added", "AniList", media.ID, "characters", len(anime.Characters))
+
+	return anime, nil
+}
+
+func date(v anilistDate) models.AnimeDate {
+	return models.AnimeDate{
+		Year:  v.Year,
+		Month: v.Month,
+		Day:   v.Day,
+	}
+}
+
+func image(v anilistImage) []models.AnimeImage {
+	large := v.ExtraLarge
+	if large == "" {
+		large = v.Large
+	}
+	small := v.Medium
+	if small == "" {
+		small = v.Large
+	}
+	if large == "" {
+		return nil
+	}
+	return []models.AnimeImage{{
+		Image:     large,
+		Thumbnail: small,
+	}}
+}
+
+// status maps the media states to finished, airing, upcoming or canceled.
+func status(input string) string {
+	switch input {
+	case "FINISHED":
+		return "finished"
+	case "RELEASING", "HIATUS":
+		return "airing"
+	case "NOT_YET_RELEASED":
+		return "upcoming"
+	case "CANCELLED":
+		return "canceled"
+	}
+	return ""
+}
+
+func convert(media *anilistMedia) *models.Anime {
+	anime := &models.Anime{
+		Type: media.Format,
+		Resources: models.AnimeResource{
+			Mal:     media.IDMal,
+			AniList: media.ID,
+		},
+		Titles: models.AnimeTitles{
+			Original: analyze.CleanStrings([]string{media.Title.Romaji, media.Title.Native}),
+			English:  analyze.CleanStrings([]string{media.Title.English}),
+			Synonyms: analyze.CleanStrings(media.Synonyms),
+		},
+		Description:     analyze.CleanOverview(media.Description),
+		Status:          status(media.Status),
+		CountryOfOrigin: strings.ToLower(media.CountryOfOrigin),
+		Period: models.AnimePeriod{
+			Season: strings.ToLower(media.Season),
+			Year:   media.SeasonYear,
+		},
+		StartAt: date(media.StartDate),
+		EndAt:   date(media.EndDate),
+	}
+
+	if title := media.Title.English; title != "" || anime.Description != "" {
+		if title == "" {
+			title = media.Title.Romaji
+		}
+		anime.MetaData = []models.MetaData{{
+			Language: analyze.CleanLanguage("english"),
+			Title:    title,
+			OverView: anime.Description,
+		}}
+	}
+	if media.IsAdult {
+		anime.ContentRating = analyze.MPAA5
+	}
+
+	if v := image(media.CoverImage); len(v) > 0 {
+		anime.PortraitIMG = v[0]
+		anime.Posters = v
+	}
+	if media.BannerImage != "" {
+		anime.LandscapeIMG = models.AnimeImage{Image: media.BannerImage, Thumbnail: media.BannerImage}
+		anime.Banners = []models.AnimeImage{anime.LandscapeIMG}
+	}
+
+	for _, v := range media.Genres {
+		if name := analyze.CleanTitle(v); name != "" {
+			anime.Genres = append(anime.Genres, models.AnimeGenre(name))
+		}
+	}
+	for _, v := range media.Tags {
+		if v.IsMediaSpoiler || v.IsGeneralSpoiler {
+			continue
+		}
+		if tag := analyze.CleanTag(v.Name); tag != "" {
+			anime.Tags = append(anime.Tags, models.AnimeTag(tag))
+		}
+	}
+
+	if media.Trailer != nil && media.Trailer.ID != "" {
+		anime.Trailers = []models.AnimeTrailer{{
+			IsOfficial: true,
+			HostName:   strings.ToLower(media.Trailer.Site),
+			HostKey:    media.Trailer.ID,
+		}}
+	}
+
+	for _, v := range media.ExternalLinks {
+		if v.URL == "" {
+			continue
+		}
+		anime.External = append(anime.External, models.AnimeLink{
+			Site: analyze.CleanTitle(v.Site),
+			URL:  strings.TrimSpace(v.URL),
+		})
+	}
+
+	for _, v := range media.Studios.Edges {
+		company := models.AnimeCompany{
+			ID:   models.AnimeID{AniList: v.Node.ID},
+			Name: strings.TrimSpace(v.Node.Name),
+		}
+		if v.IsMain {
+			anime.Studios = append(anime.Studios, company)
+		} else {
+			anime.Producers = append(anime.Producers, company)
+		}
+	}
+
+	anime.Relations = relations(media)
+	anime.Characters = characters(media.Characters.Edges)
+
+	return anime
+}
+
+// relations groups the related media by the nature of the relation, such as sequel or prequel.
+func relations(media *anilistMedia) []models.AnimeRelation {
+	var (
+		data   []models.AnimeRelation
+		filter = make(map[string]int)
+	)
+
+	for _, v := range media.Relations.Edges {
+		nature := analyze.CleanTitle(v.RelationType)
+		if nature == "" {
+			continue
+		}
+
+		i, found := filter[nature]
+		if !found {
+			i = len(data)
+			filter[nature] = i
+			data = append(data, models.AnimeRelation{Nature: nature})
+		}
+
+		name := v.Node.Title.Romaji
+		if name == "" {
+			name = v.Node.Title.English
+		}
+		data[i].Nodes = append(data[i].Nodes, struct {
+			ID     models.AnimeID `json:"ID"`
+			Name   string         `json:"Name"`
+			Format string         `json:"Format"`
+			Type   string         `json:"Type"`
+		}{
+			ID:     models.AnimeID{Mal: v.Node.IDMal, AniList: v.Node.ID},
+			Name:   name,
+			Format: strings.ToLower(v.Node.Format),
+			Type:   strings.ToLower(v.Node.Type),
+		})
+	}
+
+	return data
+}
+
+func characters(edges []anilistCharacter) []models.AnimeCharacter {
+	data := make([]models.AnimeCharacter, 0, len(edges))
+	for _, v := range edges {
+		character := models.AnimeCharacter{
+			ID: models.AnimeID{AniList: v.Node.ID},
+			Name: models.AnimeName{
+				Full:        v.Node.Name.Full,
+				Native:      v.Node.Name.Native,
+				Alternative: analyze.CleanStrings(v.Node.Name.Alternative),
+				Spoilers:    analyze.CleanStrings(v.Node.Name.AlternativeSpoiler),
+			},
+			Images:      image(v.Node.Image),
+			InitialAge:  analyze.ExtractNum(v.Node.Age),
+			Gender:      analyze.CleanTitle(v.Node.Gender),
+			DateOfBirth: date(v.Node.DateOfBirth),
+			Role:        analyze.CleanTitle(v.Role),
+		}
+		character.Description.AniList = analyze.CleanOverview(v.Node.Description)
+
+		for _, x := range v.VoiceActors {
+			character.VoiceActor = append(character.VoiceActor, models.AnimeVoiceActor{
+				ID: models.AnimeID{AniList: x.ID},
+				Name: models.AnimeName{
+					Full:        x.Name.Full,
+					Native:      x.Name.Native,
+					Alternative: analyze.CleanStrings(x.Name.Alternative),
+				},
+				Language:    analyze.CleanLanguage(x.LanguageV2),
+				Images:      image(x.Image),
+				Age:         x.Age,
+				Gender:      analyze.CleanTitle(x.Gender),
+				DateOfBirth: date(x.DateOfBirth),
+				DateOfDeath: date(x.DateOfDeath),
+				Home:        analyze.CleanUnicode(strings.TrimSpace(x.HomeTown)),
+			})
+		}
+
+		data = append(data, character)
+	}
+
+	return data
+}
@@ -0,0 +1,194 @@
+package anilist
+
+import "log/slog"
+
+const (
+	host = "graphql.anilist.co"
+	// pages of characters asked at most, 25 per page.
+	maxPages = 8
+)
+
+var logger = slog.Default().WithGroup("[ANILIST]")
+
+const mediaQuery = `query ($id: Int, $idMal: Int, $page: Int) {
+  Media(id: $id, idMal: $idMal, type: ANIME) {
+    id
+    idMal
+    format
+    status(version: 2)
+    countryOfOrigin
+    isAdult
+    title { romaji english native }
+    synonyms
+    description(asHtml: false)
+    startDate { year month day }
+    endDate { year month day }
+    season
+    seasonYear
+    coverImage { extraLarge large }
+    bannerImage
+    genres
+    tags { name rank isMediaSpoiler isGeneralSpoiler }
+    trailer { id site }
+    externalLinks { site url type }
+    studios { edges { isMain node { id name } } }
+    relations {
+      edges {
+        relationType(version: 2)
+        node { id idMal type format title { romaji english } }
+      }
+    }
+    characters(page: $page, perPage: 25, sort: [ROLE, RELEVANCE, ID]) {
+      pageInfo { hasNextPage }
+      edges {
+        role
+        node {
+          id
+          name { full native alternative alternativeSpoiler }
+          image { large medium }
+          description(asHtml: false)
+          age
+          gender
+          dateOfBirth { year month day }
+        }
+        voiceActors(sort: [RELEVANCE, ID]) {
+          id
+          name { full native alternative }
+          languageV2
+          image { large medium }
+          age
+          gender
+          dateOfBirth { year month day }
+          dateOfDeath { year month day }
+          homeTown
+        }
+      }
+    }
+  }
+}`
+
+type anilistRequest struct {
+	Query     string         `json:"query"`
+	Variables map[string]int `json:"variables"`
+}
+
+type anilistResponse struct {
+	Data struct {
+		Media *anilistMedia `json:"Media"`
+	} `json:"data"`
+	Errors []struct {
+		Message string `json:"message"`
+		Status  int    `json:"status"`
+	} `json:"errors"`
+}
+
+type anilistDate struct {
+	Year  int `json:"year"`
+	Month int `json:"month"`
+	Day   int `json:"day"`
+}
+
+type anilistImage struct {
+	ExtraLarge string `json:"extraLarge"`
+	Large      string `json:"large"`
+	Medium     string `json:"medium"`
+}
+
+type anilistMedia struct {
+	ID              int    `json:"id"`
+	IDMal           int    `json:"idMal"`
+	Format          string `json:"format"`
+	Status          string `json:"status"`
+	CountryOfOrigin string `json:"countryOfOrigin"`
+	IsAdult         bool   `json:"isAdult"`
+	Title           struct {
+		Romaji  string `json:"romaji"`
+		English string `json:"english"`
+		Native  string `json:"native"`
+	} `json:"title"`
+	Synonyms    []string     `json:"synonyms"`
+	Description string       `json:"description"`
+	StartDate   anilistDate  `json:"startDate"`
+	EndDate     anilistDate  `json:"endDate"`
+	Season      string       `json:"season"`
+	SeasonYear  int          `json:"seasonYear"`
+	CoverImage  anilistImage `json:"coverImage"`
+	BannerImage string       `json:"bannerImage"`
+	Genres      []string     `json:"genres"`
+	Tags        []struct {
+		Name             string `json:"name"`
+		Rank             int    `json:"rank"`
+		IsMediaSpoiler   bool   `json:"isMediaSpoiler"`
+		IsGeneralSpoiler bool   `json:"isGeneralSpoiler"`
+	} `json:"tags"`
+	Trailer *struct {
+		ID   string `json:"id"`
+		Site string `json:"site"`
+	} `json:"trailer"`
+	ExternalLinks []struct {
+		Site string `json:"site"`
+		URL  string `json:"url"`
+		Type string `json:"type"`
+	} `json:"externalLinks"`
+	Studios struct {
+		Edges []struct {
+			IsMain bool `json:"isMain"`
+			Node   struct {
+				ID   int    `json:"id"`
+				Name string `json:"name"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"studios"`
+	Relations struct {
+		Edges []struct {
+			RelationType string `json:"relationType"`
+			Node         struct {
+				ID     int    `json:"id"`
+				IDMal  int    `json:"idMal"`
+				Type   string `json:"type"`
+				Format string `json:"format"`
+				Title  struct {
+					Romaji  string `json:"romaji"`
+					English string `json:"english"`
+				} `json:"title"`
+			} `json:"node"`
+		} `json:"edges"`
+	} `json:"relations"`
+	Characters struct {
+		PageInfo struct {
+			HasNextPage bool `json:"hasNextPage"`
+		} `json:"pageInfo"`
+		Edges []anilistCharacter `json:"edges"`
+	} `json:"characters"`
+}
+
+type anilistName struct {
+	Full               string   `json:"full"`
+	Native             string   `json:"native"`
+	Alternative        []string `json:"alternative"`
+	AlternativeSpoiler []string `json:"alternativeSpoiler"`
+}
+
+type anilistCharacter struct {
+	Role string `json:"role"`
+	Node struct {
+		ID          int          `json:"id"`
+		Name        anilistName  `json:"name"`
+		Image       anilistImage `json:"image"`
+		Description string       `json:"description"`
+		Age         string       `json:"age"`
+		Gender      string       `json:"gender"`
+		DateOfBirth anilistDate  `json:"dateOfBirth"`
+	} `json:"node"`
+	VoiceActors []struct {
+		ID          int          `json:"id"`
+		Name        anilistName  `json:"name"`
+		LanguageV2  string       `json:"languageV2"`
+		Image       anilistImage `json:"image"`
+		Age         int          `json:"age"`
+		Gender      string       `json:"gender"`
+		DateOfBirth anilistDate  `json:"dateOfBirth"`
+		DateOfDeath anilistDate  `json:"dateOfDeath"`
+		HomeTown    string       `json:"homeTown"`
+	} `json:"voiceActors"`
+}
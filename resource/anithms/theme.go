@@ -37,11 +37,12 @@ func Fetch(ctx context.Context, original, english, romanji string, year int, sea
 	for !stop {
 		if err = func() error {
 			stop = true
-			body, err := client.Do(ctx, args)
+			body, err := client.Stream(ctx, args)
 			if err != nil {
 				logger.Error("cannot get data", "error", err)
 				return err
 			}
+			defer body.Close()
 
 			var data animeThemesSearch
 			err = json.NewDecoder(body).Decode(&data)
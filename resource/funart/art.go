@@ -27,7 +27,7 @@ func Movie(ctx context.Context, tmdbid int) (*FunArtMovie, error) {
 	var art FunArtMovie
 	for i := 0; i < length(); i += 1 {
 		if err = func() error {
-			body, err := client.Do(ctx, &client.Args{
+			body, err := client.Stream(ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
 				Endpoint: endpoint,
@@ -37,6 +37,7 @@ func Movie(ctx context.Context, tmdbid int) (*FunArtMovie, error) {
 				endpoint.RawQuery = "api_key=" + generate()
 				return err
 			}
+			defer body.Close()
 
 			err = json.NewDecoder(body).Decode(&art)
 			if err != nil {
@@ -77,7 +78,7 @@ func TV(ctx context.Context, tvdbid int) (*FunArtTv, error) {
 	var art FunArtTv
 	for i := 0; i < length(); i += 1 {
 		if err = func() error {
-			body, err := client.Do(ctx, &client.Args{
+			body, err := client.Stream(ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
 				Endpoint: endpoint,
@@ -87,6 +88,7 @@ func TV(ctx context.Context, tvdbid int) (*FunArtTv, error) {
 				endpoint.RawQuery = "api_key=" + generate()
 				return err
 			}
+			defer body.Close()
 
 			err = json.NewDecoder(body).Decode(&art)
 			if err != nil {
@@ -0,0 +1,129 @@
+package mal
+
+import (
+	"errors"
+	"log/slog"
+	"net/url"
+	"strings"
+	"sync"
+
+	"github.com/anicine/anicine-scraper/client"
+)
+
+var (
+	base = &url.URL{
+		Scheme: "https",
+		Host:   "api.jikan.moe",
+		Path:   "/v4",
+	}
+	mutex  sync.RWMutex
+	logger = slog.Default().WithGroup("[MAL]")
+)
+
+func init() {
+	// the public API allows 3 requests per second and 60 per minute
+	client.SetPolicy(base.Host, client.Policy{Rate: 1, Burst: 3, Concurrency: 2})
+}
+
+// SetEndpoint points the package to another instance of the Jikan API, such as a self
+// hosted one, for example http://localhost:8080/v4.
+func SetEndpoint(link string) error {
+	u, err := url.Parse(strings.TrimSuffix(strings.TrimSpace(link), "/"))
+	if err != nil {
+		return err
+	}
+	if u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+		return errors.New("invalid jikan url: " + link)
+	}
+
+	mutex.Lock()
+	defer mutex.Unlock()
+	base = u
+	return nil
+}
+
+func endpoint(path string) *url.URL {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	u := *base
+	u.Path += path
+	return &u
+}
+
+type jikanDate struct {
+	Day   int `json:"day"`
+	Month int `json:"month"`
+	Year  int `json:"year"`
+}
+
+type jikanEntry struct {
+	MalID int    `json:"mal_id"`
+	Type  string `json:"type"`
+	Name  string `json:"name"`
+	URL   string `json:"url"`
+}
+
+type jikanImages struct {
+	ImageURL      string `json:"image_url"`
+	SmallImageURL string `json:"small_image_url"`
+	LargeImageURL string `json:"large_image_url"`
+}
+
+type jikanAnime struct {
+	MalID  int `json:"mal_id"`
+	Images struct {
+		Jpg  jikanImages `json:"jpg"`
+		Webp jikanImages `json:"webp"`
+	} `json:"images"`
+	Trailer struct {
+		YoutubeID string `json:"youtube_id"`
+		URL       string `json:"url"`
+		EmbedURL  string `json:"embed_url"`
+	} `json:"trailer"`
+	Titles []struct {
+		Type  string `json:"type"`
+		Title string `json:"title"`
+	} `json:"titles"`
+	Title         string   `json:"title"`
+	TitleEnglish  string   `json:"title_english"`
+	TitleJapanese string   `json:"title_japanese"`
+	TitleSynonyms []string `json:"title_synonyms"`
+	Type          string   `json:"type"`
+	Source        string   `json:"source"`
+	Episodes      int      `json:"episodes"`
+	Status        string   `json:"status"`
+	Airing        bool     `json:"airing"`
+	Aired         struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Prop struct {
+			From jikanDate `json:"from"`
+			To   jikanDate `json:"to"`
+		} `json:"prop"`
+	} `json:"aired"`
+	Duration       string       `json:"duration"`
+	Rating         string       `json:"rating"`
+	Synopsis       string       `json:"synopsis"`
+	Season         string       `json:"season"`
+	Year           int          `json:"year"`
+	Producers      []jikanEntry `json:"producers"`
+	Licensors      []jikanEntry `json:"licensors"`
+	Studios        []jikanEntry `json:"studios"`
+	Genres         []jikanEntry `json:"genres"`
+	ExplicitGenres []jikanEntry `json:"explicit_genres"`
+	Themes         []jikanEntry `json:"themes"`
+	Demographics   []jikanEntry `json:"demographics"`
+	Relations      []struct {
+		Relation string       `json:"relation"`
+		Entry    []jikanEntry `json:"entry"`
+	} `json:"relations"`
+	External []struct {
+		Name string `json:"name"`
+		URL  string `json:"url"`
+	} `json:"external"`
+}
+
+type jikanResponse struct {
+	Data jikanAnime `json:"data"`
+}
@@ -0,0 +1,226 @@
+package mal
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// Fetch returns the anime of the MyAnimeList id along with the info the scrapers and the
+// mappers expect, so a pipeline can start from the id alone.
+func Fetch(ctx context.Context, id int) (*models.Anime, *models.AnimeInfo, error) {
+	body, err := client.Do(ctx, &client.Args{
+		Proxy:  true,
+		Method: http.MethodGet,
+		Headers: map[string]string{
+			"Accept": "application/json",
+		},
+		Endpoint: endpoint("/anime/" + strconv.Itoa(id) + "/full"),
+	})
+	if err != nil {
+		logger.Error("cannot get data", "MAL", id, "error", err)
+		return nil, nil, errs.Wrap(err, "mal", errs.StageFetch)
+	}
+
+	var data jikanResponse
+	if err = json.NewDecoder(body).Decode(&data); err != nil {
+		logger.Error("cannot decode JSON data", "MAL", id, "error", err)
+		return nil, nil, errs.Wrap(err, "mal", errs.StageFetch)
+	}
+	if data.Data.MalID != id {
+		return nil, nil, errs.Wrap(errs.ErrBadData, "mal", errs.StageFetch)
+	}
+
+	anime := convert(&data.Data)
+	info := &models.AnimeInfo{
+		Title: data.Data.Title,
+		Query: analyze.CleanTitle(data.Data.Title),
+		Type:  kind(data.Data.Type),
+		MalID: id,
+		SD:    anime.StartAt,
+		ED:    anime.EndAt,
+	}
+
+	logger.Info("anime was added", "MAL", id)
+
+	return anime, info, nil
+}
+
+// Info returns only the info of the anime, see Fetch.
+func Info(ctx context.Context, id int) (*models.AnimeInfo, error) {
+	_, info, err := Fetch(ctx, id)
+	return info, err
+}
+
+// kind maps the types of MyAnimeList to the ones of models.AnimeInfo: tv, movie, ova, ona,
+// special and music.
+func kind(input string) string {
+	input = strings.ToLower(input)
+	switch {
+	case input == "":
+		return "tv"
+	case strings.Contains(input, "special"):
+		return "special"
+	case input == "cm", input == "pv":
+		return "special"
+	}
+	return input
+}
+
+// status maps the airing states to finished, airing or upcoming.
+func status(input string) string {
+	switch strings.ToLower(input) {
+	case "finished airing":
+		return "finished"
+	case "currently airing":
+		return "airing"
+	case "not yet aired":
+		return "upcoming"
+	}
+	return ""
+}
+
+func date(v jikanDate) models.AnimeDate {
+	return models.AnimeDate{
+		Year:  v.Year,
+		Month: v.Month,
+		Day:   v.Day,
+	}
+}
+
+func companies(input []jikanEntry) []models.AnimeCompany {
+	data := make([]models.AnimeCompany, 0, len(input))
+	for _, v := range input {
+		if v.Name == "" {
+			continue
+		}
+		data = append(data, models.AnimeCompany{
+			ID:   models.AnimeID{Mal: v.MalID},
+			Name: strings.TrimSpace(v.Name),
+		})
+	}
+	return data
+}
+
+func convert(v *jikanAnime) *models.Anime {
+	anime := &models.Anime{
+		Type: strings.ToUpper(kind(v.Type)),
+		Resources: models.AnimeResource{
+			Mal: v.MalID,
+		},
+		Titles: models.AnimeTitles{
+			Original: analyze.CleanStrings([]string{v.Title, v.TitleJapanese}),
+			English:  analyze.CleanStrings([]string{v.TitleEnglish}),
+			Synonyms: analyze.CleanStrings(v.TitleSynonyms),
+		},
+		Description: analyze.CleanOverview(v.Synopsis),
+		Status:      status(v.Status),
+		StartAt:     date(v.Aired.Prop.From),
+		EndAt:       date(v.Aired.Prop.To),
+		Studios:     companies(v.Studios),
+		Producers:   companies(v.Producers),
+		Licensors:   companies(v.Licensors),
+	}
+
+	if v.Season != "" {
+		anime.Period = models.AnimePeriod{
+			Season: strings.ToLower(v.Season),
+			Year:   v.Year,
+		}
+	} else {
+		anime.Period = analyze.ExtractAnimePeriod(anime.StartAt)
+	}
+
+	if title := v.TitleEnglish; title != "" || anime.Description != "" {
+		if title == "" {
+			title = v.Title
+		}
+		anime.MetaData = []models.MetaData{{
+			Language: analyze.CleanLanguage("english"),
+			Title:    title,
+			OverView: anime.Description,
+		}}
+	}
+
+	// the rating reads like "PG-13 - Teens 13 or older"
+	if rating, _, found := strings.Cut(v.Rating, " - "); found {
+		anime.ContentRating = strings.TrimSpace(rating)
+	}
+
+	if v.Images.Jpg.LargeImageURL != "" || v.Images.Jpg.ImageURL != "" {
+		large := v.Images.Jpg.LargeImageURL
+		if large == "" {
+			large = v.Images.Jpg.ImageURL
+		}
+		anime.PortraitIMG = models.AnimeImage{
+			Image:     large,
+			Thumbnail: v.Images.Jpg.ImageURL,
+		}
+		anime.Posters = []models.AnimeImage{anime.PortraitIMG}
+	}
+
+	if key := v.Trailer.YoutubeID; key != "" || v.Trailer.URL != "" {
+		if key == "" {
+			key = analyze.CleanYTKey(v.Trailer.URL)
+		}
+		if key != "" {
+			anime.Trailers = []models.AnimeTrailer{{
+				IsOfficial: true,
+				HostName:   "youtube",
+				HostKey:    key,
+			}}
+		}
+	}
+
+	for _, x := range append(v.Genres, v.ExplicitGenres...) {
+		if name := analyze.CleanTitle(x.Name); name != "" {
+			anime.Genres = append(anime.Genres, models.AnimeGenre(name))
+		}
+	}
+	for _, x := range append(v.Themes, v.Demographics...) {
+		if tag := analyze.CleanTag(x.Name); tag != "" {
+			anime.Tags = append(anime.Tags, models.AnimeTag(tag))
+		}
+	}
+
+	for _, x := range v.External {
+		if x.URL == "" {
+			continue
+		}
+		anime.External = append(anime.External, models.AnimeLink{
+			Site: analyze.CleanTitle(x.Name),
+			URL:  strings.TrimSpace(x.URL),
+		})
+	}
+
+	for _, x := range v.Relations {
+		relation := models.AnimeRelation{
+			Nature: analyze.CleanTitle(x.Relation),
+		}
+		if relation.Nature == "" {
+			continue
+		}
+		for _, y := range x.Entry {
+			relation.Nodes = append(relation.Nodes, struct {
+				ID     models.AnimeID `json:"ID"`
+				Name   string         `json:"Name"`
+				Format string         `json:"Format"`
+				Type   string         `json:"Type"`
+			}{
+				ID:   models.AnimeID{Mal: y.MalID},
+				Name: y.Name,
+				Type: strings.ToLower(y.Type),
+			})
+		}
+		anime.Relations = append(anime.Relations, relation)
+	}
+
+	return anime
+}
@@ -0,0 +1,136 @@
+package simkl
+
+import (
+	"bytes"
+	"log/slog"
+	"strconv"
+	"sync"
+)
+
+var (
+	tokens = []string{}
+	idx    int
+	mutex  sync.RWMutex
+	logger = slog.Default().WithGroup("[SIMKL]")
+)
+
+func generate() string {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if len(tokens) == 0 {
+		return ""
+	}
+
+	token := tokens[idx]
+	idx = (idx + 1) % len(tokens)
+
+	return token
+}
+
+func length() int {
+	mutex.RLock()
+	defer mutex.RUnlock()
+
+	return len(tokens)
+}
+
+func SetTokens(data ...string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	for _, v := range data {
+		if v != "" {
+			tokens = append(tokens, v)
+		}
+	}
+}
+
+// simklID is an id the API sends either as a number or as a string.
+type simklID string
+
+func (v *simklID) UnmarshalJSON(data []byte) error {
+	if bytes.Equal(data, []byte("null")) {
+		*v = ""
+		return nil
+	}
+	if s, err := strconv.Unquote(string(data)); err == nil {
+		*v = simklID(s)
+		return nil
+	}
+	*v = simklID(data)
+	return nil
+}
+
+func (v simklID) Int() int64 {
+	n, _ := strconv.ParseInt(string(v), 10, 64)
+	return n
+}
+
+type simklIDs struct {
+	Simkl       simklID `json:"simkl"`
+	SimklID     simklID `json:"simkl_id"`
+	Slug        simklID `json:"slug"`
+	Mal         simklID `json:"mal"`
+	AniDB       simklID `json:"anidb"`
+	AniList     simklID `json:"anilist"`
+	Kitsu       simklID `json:"kitsu"`
+	LiveChart   simklID `json:"livechart"`
+	AniSearch   simklID `json:"anisearch"`
+	AnimePlanet simklID `json:"animeplanet"`
+	NotifyMoe   simklID `json:"notifymoe"`
+	TVDB        simklID `json:"tvdb"`
+	TMDB        simklID `json:"tmdb"`
+	IMDB        simklID `json:"imdb"`
+}
+
+// id returns the simkl id, which is named differently by the search endpoints.
+func (v simklIDs) id() int64 {
+	if n := v.Simkl.Int(); n != 0 {
+		return n
+	}
+	return v.SimklID.Int()
+}
+
+type simklSearch struct {
+	Type    string   `json:"type"`
+	Title   string   `json:"title"`
+	TitleEn string   `json:"title_en"`
+	Year    int      `json:"year"`
+	Ids     simklIDs `json:"ids"`
+}
+
+type simklAnime struct {
+	Title          string   `json:"title"`
+	EnTitle        string   `json:"en_title"`
+	Year           int      `json:"year"`
+	Type           string   `json:"type"`
+	AnimeType      string   `json:"anime_type"`
+	Poster         string   `json:"poster"`
+	Fanart         string   `json:"fanart"`
+	FirstAired     string   `json:"first_aired"`
+	Status         string   `json:"status"`
+	Runtime        int      `json:"runtime"`
+	Certification  string   `json:"certification"`
+	Country        string   `json:"country"`
+	Overview       string   `json:"overview"`
+	Genres         []string `json:"genres"`
+	TotalEpisodes  int      `json:"total_episodes"`
+	AlternateTitle []struct {
+		Name string `json:"name"`
+		Type string `json:"type"`
+	} `json:"alt_titles"`
+	Ids simklIDs `json:"ids"`
+}
+
+type simklEpisode struct {
+	Title       string  `json:"title"`
+	Description string  `json:"description"`
+	Season      int     `json:"season"`
+	Episode     float32 `json:"episode"`
+	Type        string  `json:"type"`
+	Aired       bool    `json:"aired"`
+	Img         string  `json:"img"`
+	Date        string  `json:"date"`
+	Ids         struct {
+		SimklID simklID `json:"simkl_id"`
+	} `json:"ids"`
+}
@@ -0,0 +1,267 @@
+package simkl
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/internal/shared"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// get queries the path and decodes the JSON response into data, moving to the next
+// token of the pool after every failure.
+func get(ctx context.Context, path string, query url.Values, data any) error {
+	if length() == 0 {
+		return errors.New("no simkl token was set")
+	}
+
+	var err error
+	for i := 0; i < length(); i += 1 {
+		if err = func() error {
+			body, err := client.Stream(ctx, &client.Args{
+				Proxy:  true,
+				Method: http.MethodGet,
+				Headers: map[string]string{
+					"Content-Type":  "application/json",
+					"simkl-api-key": generate(),
+				},
+				Endpoint: &url.URL{
+					Scheme:   "https",
+					Host:     "api.simkl.com",
+					Path:     path,
+					RawQuery: query.Encode(),
+				},
+			})
+			if err != nil {
+				logger.Warn("retry to query simkl", "path", path, "error", err)
+				return err
+			}
+			defer body.Close()
+
+			err = json.NewDecoder(body).Decode(data)
+			if err != nil {
+				logger.Error("cannot decode JSON data", "path", path, "error", err)
+				return err
+			}
+
+			return nil
+		}(); err != nil {
+			if errors.Is(err, context.Canceled) || errors.Is(err, errs.ErrNotFound) {
+				return err
+			}
+			continue
+		}
+		break
+	}
+
+	return err
+}
+
+// Lookup returns the simkl id of the anime known on the site, one of mal, anidb,
+// anilist, tvdb, tmdb or imdb.
+func Lookup(ctx context.Context, site, id string) (int64, error) {
+	query := url.Values{site: {id}}
+	if site == "tmdb" {
+		query.Set("type", "show")
+	}
+
+	var data []simklSearch
+	if err := get(ctx, "/search/id", query, &data); err != nil {
+		return 0, errs.Wrap(err, "simkl", errs.StageSearch)
+	}
+
+	for _, v := range data {
+		if n := v.Ids.id(); n != 0 {
+			return n, nil
+		}
+	}
+
+	return 0, errs.Wrap(errs.ErrNotFound, "simkl", errs.StageFilter)
+}
+
+// Search returns the simkl id of the anime that best matches the title, the year
+// is skipped when it is zero.
+func Search(ctx context.Context, title string, year int) (int64, error) {
+	var data []simklSearch
+	if err := get(ctx, "/search/anime", url.Values{"q": {title}}, &data); err != nil {
+		return 0, errs.Wrap(err, "simkl", errs.StageSearch)
+	}
+
+	var (
+		id    int64
+		best  float64
+		clean = analyze.CleanTitle(title)
+	)
+	for _, v := range data {
+		if year != 0 && v.Year != 0 && v.Year != year {
+			continue
+		}
+
+		score := max(
+			shared.TextAdvancedSimilarity(clean, analyze.CleanTitle(v.Title)),
+			shared.TextAdvancedSimilarity(clean, analyze.CleanTitle(v.TitleEn)),
+		)
+		if score > 85 && score > best {
+			best = score
+			id = v.Ids.id()
+		}
+	}
+
+	if id == 0 {
+		return 0, errs.Wrap(errs.ErrNotFound, "simkl", errs.StageFilter)
+	}
+
+	return id, nil
+}
+
+// Anime returns the anime with the ids of the other sites, ready for analyze.MergeAnimeResource.
+func Anime(ctx context.Context, id int64) (*models.Anime, error) {
+	var data simklAnime
+	err := get(ctx, "/anime/"+strconv.FormatInt(id, 10), url.Values{"extended": {"full"}}, &data)
+	if err != nil {
+		return nil, errs.Wrap(err, "simkl", errs.StageFetch)
+	}
+	if data.Ids.id() != id {
+		return nil, errs.Wrap(errs.ErrBadData, "simkl", errs.StageFetch)
+	}
+
+	anime := &models.Anime{
+		Type: strings.ToUpper(data.AnimeType),
+		Resources: models.AnimeResource{
+			Mal:         int(data.Ids.Mal.Int()),
+			AniList:     int(data.Ids.AniList.Int()),
+			AniDB:       int(data.Ids.AniDB.Int()),
+			Kitsu:       string(data.Ids.Kitsu),
+			TVDBID:      data.Ids.TVDB.Int(),
+			TMDBID:      data.Ids.TMDB.Int(),
+			IMDBID:      string(data.Ids.IMDB),
+			AniSearch:   data.Ids.AniSearch.Int(),
+			LiveChart:   data.Ids.LiveChart.Int(),
+			NotifyMoe:   string(data.Ids.NotifyMoe),
+			AnimePlanet: string(data.Ids.AnimePlanet),
+		},
+		Titles: models.AnimeTitles{
+			Original: analyze.CleanStrings([]string{data.Title}),
+			English:  analyze.CleanStrings([]string{data.EnTitle}),
+		},
+		Description:     analyze.CleanOverview(data.Overview),
+		ContentRating:   data.Certification,
+		CountryOfOrigin: analyze.CleanCountry(data.Country),
+	}
+
+	for _, v := range data.AlternateTitle {
+		if v.Name != "" {
+			anime.Titles.Synonyms = append(anime.Titles.Synonyms, v.Name)
+		}
+	}
+	anime.Titles.Synonyms = analyze.CleanStrings(anime.Titles.Synonyms)
+
+	for _, v := range data.Genres {
+		if name := analyze.CleanTitle(v); name != "" {
+			anime.Genres = append(anime.Genres, models.AnimeGenre(name))
+		}
+	}
+
+	if data.Poster != "" {
+		anime.PortraitIMG = models.AnimeImage{
+			Image:     "https://simkl.in/posters/" + data.Poster + "_m.jpg",
+			Thumbnail: "https://simkl.in/posters/" + data.Poster + "_cm.jpg",
+		}
+	}
+	if data.Fanart != "" {
+		anime.LandscapeIMG = models.AnimeImage{
+			Image:     "https://simkl.in/fanart/" + data.Fanart + "_medium.jpg",
+			Thumbnail: "https://simkl.in/fanart/" + data.Fanart + "_mobile.jpg",
+		}
+	}
+
+	if t, err := time.Parse(time.RFC3339, data.FirstAired); err == nil {
+		anime.StartAt = models.AnimeDate{
+			Year:  t.Year(),
+			Month: int(t.Month()),
+			Day:   t.Day(),
+		}
+		anime.Period = analyze.ExtractAnimePeriod(anime.StartAt)
+	}
+
+	logger.Info("anime was added", "SIMKL", id)
+
+	return anime, nil
+}
+
+// Episodes returns the episodes of the anime with their simkl ids, specials included.
+func Episodes(ctx context.Context, id int64) ([]models.AnimeEpisode, error) {
+	var data []simklEpisode
+	err := get(ctx, "/anime/episodes/"+strconv.FormatInt(id, 10), url.Values{"extended": {"full"}}, &data)
+	if err != nil {
+		return nil, errs.Wrap(err, "simkl", errs.StageFetch)
+	}
+
+	episodes := make([]models.AnimeEpisode, 0, len(data))
+	for _, v := range data {
+		episode := models.AnimeEpisode{
+			EnTitle:      analyze.CleanUnicode(v.Title),
+			Aired:        v.Aired,
+			Special:      v.Type == "special",
+			SeasonNumber: v.Season,
+			Number:       v.Episode,
+			Resources: models.AnimeEpisodeResources{
+				SimklID: v.Ids.SimklID.Int(),
+			},
+		}
+		if v.Img != "" {
+			episode.ThumbnailsIMG = models.AnimeImage{
+				Image:     "https://simkl.in/episodes/" + v.Img + "_w.jpg",
+				Thumbnail: "https://simkl.in/episodes/" + v.Img + "_m.jpg",
+			}
+		}
+		if t, err := time.Parse(time.RFC3339, v.Date); err == nil {
+			episode.ReleaseTime = models.AnimeTime{
+				Year:  t.Year(),
+				Month: int(t.Month()),
+				Day:   t.Day(),
+				Unix:  t.Unix(),
+			}
+		}
+		episodes = append(episodes, episode)
+	}
+
+	logger.Info("episodes were added", "SIMKL", id, "episodes", len(episodes))
+
+	return episodes, nil
+}
+
+// Resolve returns the simkl id of the anime by its mal id, or by its title and year when
+// the mal id is unknown to simkl.
+func Resolve(ctx context.Context, info *models.AnimeInfo) (int64, error) {
+	if info.MalID != 0 {
+		id, err := Lookup(ctx, "mal", strconv.Itoa(info.MalID))
+		if err == nil {
+			return id, nil
+		}
+		if errors.Is(err, context.Canceled) {
+			return 0, err
+		}
+	}
+
+	return Search(ctx, info.Title, info.SD.Year)
+}
+
+// Find resolves the anime and returns it.
+func Find(ctx context.Context, info *models.AnimeInfo) (*models.Anime, error) {
+	id, err := Resolve(ctx, info)
+	if err != nil {
+		return nil, err
+	}
+
+	return Anime(ctx, id)
+}
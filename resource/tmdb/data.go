@@ -0,0 +1,224 @@
+package tmdb
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+)
+
+const (
+	original  = "https://image.tmdb.org/t/p/original"
+	thumbnail = "https://image.tmdb.org/t/p/w500"
+	// languages asked for the images, null keeps the ones without text.
+	imageLanguages = "en,ja,null"
+)
+
+var (
+	key    string
+	mutex  sync.RWMutex
+	logger = slog.Default().WithGroup("[TMDB]")
+)
+
+// SetKey sets the API key, either a v3 key sent as a query parameter or a v4 read access token
+// sent as a bearer token.
+func SetKey(data string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	key = strings.TrimSpace(data)
+}
+
+func token() string {
+	mutex.RLock()
+	defer mutex.RUnlock()
+	return key
+}
+
+type tmdbImage struct {
+	AspectRatio float64 `json:"aspect_ratio"`
+	Height      int     `json:"height"`
+	Width       int     `json:"width"`
+	Iso6391     string  `json:"iso_639_1"`
+	FilePath    string  `json:"file_path"`
+	VoteAverage float64 `json:"vote_average"`
+}
+
+type tmdbImages struct {
+	Backdrops []tmdbImage `json:"backdrops"`
+	Logos     []tmdbImage `json:"logos"`
+	Posters   []tmdbImage `json:"posters"`
+	Stills    []tmdbImage `json:"stills"`
+}
+
+type tmdbVideos struct {
+	Results []struct {
+		Iso6391  string `json:"iso_639_1"`
+		Key      string `json:"key"`
+		Site     string `json:"site"`
+		Type     string `json:"type"`
+		Official bool   `json:"official"`
+	} `json:"results"`
+}
+
+type tmdbTranslations struct {
+	Translations []struct {
+		Iso31661 string `json:"iso_3166_1"`
+		Iso6391  string `json:"iso_639_1"`
+		Data     struct {
+			Name     string `json:"name"`
+			Title    string `json:"title"`
+			Overview string `json:"overview"`
+		} `json:"data"`
+	} `json:"translations"`
+}
+
+type tmdbExternalIDs struct {
+	ImdbID     string `json:"imdb_id"`
+	TvdbID     int64  `json:"tvdb_id"`
+	WikidataID string `json:"wikidata_id"`
+}
+
+type tmdbAlternativeTitles struct {
+	// Results is set for the shows and Titles for the movies.
+	Results []tmdbAlternativeTitle `json:"results"`
+	Titles  []tmdbAlternativeTitle `json:"titles"`
+}
+
+type tmdbAlternativeTitle struct {
+	Iso31661 string `json:"iso_3166_1"`
+	Title    string `json:"title"`
+}
+
+type tmdbKeywords struct {
+	// Results is set for the shows and Keywords for the movies.
+	Results  []tmdbKeyword `json:"results"`
+	Keywords []tmdbKeyword `json:"keywords"`
+}
+
+type tmdbKeyword struct {
+	Name string `json:"name"`
+}
+
+type tmdbCompany struct {
+	ID            int64  `json:"id"`
+	Name          string `json:"name"`
+	OriginCountry string `json:"origin_country"`
+}
+
+type tmdbGenre struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
+
+type tmdbTV struct {
+	ID                  int64          `json:"id"`
+	Name                string         `json:"name"`
+	OriginalName        string         `json:"original_name"`
+	OriginalLanguage    string         `json:"original_language"`
+	Overview            string         `json:"overview"`
+	FirstAirDate        string         `json:"first_air_date"`
+	LastAirDate         string         `json:"last_air_date"`
+	Status              string         `json:"status"`
+	PosterPath          string         `json:"poster_path"`
+	BackdropPath        string         `json:"backdrop_path"`
+	Homepage            string         `json:"homepage"`
+	OriginCountry       []string       `json:"origin_country"`
+	EpisodeRunTime      []int          `json:"episode_run_time"`
+	Genres              []tmdbGenre    `json:"genres"`
+	ProductionCompanies []tmdbCompany  `json:"production_companies"`
+	Networks            []tmdbCompany  `json:"networks"`
+	Seasons             []tmdbTVSeason `json:"seasons"`
+	ContentRatings      struct {
+		Results []struct {
+			Iso31661 string `json:"iso_3166_1"`
+			Rating   string `json:"rating"`
+		} `json:"results"`
+	} `json:"content_ratings"`
+	Images            tmdbImages            `json:"images"`
+	Videos            tmdbVideos            `json:"videos"`
+	Translations      tmdbTranslations      `json:"translations"`
+	ExternalIDs       tmdbExternalIDs       `json:"external_ids"`
+	AlternativeTitles tmdbAlternativeTitles `json:"alternative_titles"`
+	Keywords          tmdbKeywords          `json:"keywords"`
+}
+
+type tmdbTVSeason struct {
+	ID           int64  `json:"id"`
+	SeasonNumber int    `json:"season_number"`
+	EpisodeCount int    `json:"episode_count"`
+	AirDate      string `json:"air_date"`
+}
+
+type tmdbSeason struct {
+	ID           int64  `json:"id"`
+	Name         string `json:"name"`
+	Overview     string `json:"overview"`
+	AirDate      string `json:"air_date"`
+	PosterPath   string `json:"poster_path"`
+	SeasonNumber int    `json:"season_number"`
+	Episodes     []struct {
+		ID             int64   `json:"id"`
+		Name           string  `json:"name"`
+		Overview       string  `json:"overview"`
+		AirDate        string  `json:"air_date"`
+		EpisodeNumber  int     `json:"episode_number"`
+		EpisodeType    string  `json:"episode_type"`
+		Runtime        int     `json:"runtime"`
+		SeasonNumber   int     `json:"season_number"`
+		StillPath      string  `json:"still_path"`
+		VoteAverage    float64 `json:"vote_average"`
+		ProductionCode string  `json:"production_code"`
+	} `json:"episodes"`
+	Images       tmdbImages       `json:"images"`
+	Videos       tmdbVideos       `json:"videos"`
+	Translations tmdbTranslations `json:"translations"`
+	ExternalIDs  tmdbExternalIDs  `json:"external_ids"`
+}
+
+type tmdbMovie struct {
+	ID                  int64         `json:"id"`
+	Title               string        `json:"title"`
+	OriginalTitle       string        `json:"original_title"`
+	OriginalLanguage    string        `json:"original_language"`
+	Overview            string        `json:"overview"`
+	ReleaseDate         string        `json:"release_date"`
+	Runtime             int           `json:"runtime"`
+	Status              string        `json:"status"`
+	PosterPath          string        `json:"poster_path"`
+	BackdropPath        string        `json:"backdrop_path"`
+	Homepage            string        `json:"homepage"`
+	ImdbID              string        `json:"imdb_id"`
+	OriginCountry       []string      `json:"origin_country"`
+	Genres              []tmdbGenre   `json:"genres"`
+	ProductionCompanies []tmdbCompany `json:"production_companies"`
+	ReleaseDates        struct {
+		Results []struct {
+			Iso31661     string `json:"iso_3166_1"`
+			ReleaseDates []struct {
+				Certification string `json:"certification"`
+				ReleaseDate   string `json:"release_date"`
+				Type          int    `json:"type"`
+			} `json:"release_dates"`
+		} `json:"results"`
+	} `json:"release_dates"`
+	Images            tmdbImages            `json:"images"`
+	Videos            tmdbVideos            `json:"videos"`
+	Translations      tmdbTranslations      `json:"translations"`
+	ExternalIDs       tmdbExternalIDs       `json:"external_ids"`
+	AlternativeTitles tmdbAlternativeTitles `json:"alternative_titles"`
+	Keywords          tmdbKeywords          `json:"keywords"`
+}
+
+type tmdbSearch struct {
+	Results []struct {
+		ID               int64   `json:"id"`
+		Name             string  `json:"name"`
+		OriginalName     string  `json:"original_name"`
+		Title            string  `json:"title"`
+		OriginalTitle    string  `json:"original_title"`
+		OriginalLanguage string  `json:"original_language"`
+		FirstAirDate     string  `json:"first_air_date"`
+		ReleaseDate      string  `json:"release_date"`
+		GenreIDs         []int   `json:"genre_ids"`
+		Popularity       float64 `json:"popularity"`
+	} `json:"results"`
+}
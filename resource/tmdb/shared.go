@@ -0,0 +1,307 @@
+package tmdb
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/shared"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// get queries the path of the v3 API and decodes the JSON response into data.
+func get(ctx context.Context, path string, query url.Values, data any) error {
+	k := token()
+	if k == "" {
+		return errors.New("no tmdb key was set")
+	}
+
+	headers := map[string]string{
+		"Accept": "application/json",
+	}
+	// v4 read access tokens are JWTs, v3 keys are plain hex strings.
+	if strings.HasPrefix(k, "eyJ") {
+		headers["Authorization"] = "Bearer " + k
+	} else {
+		query.Set("api_key", k)
+	}
+
+	body, err := client.Do(ctx, &client.Args{
+		Proxy:   true,
+		Method:  http.MethodGet,
+		Headers: headers,
+		Endpoint: &url.URL{
+			Scheme:   "https",
+			Host:     "api.themoviedb.org",
+			Path:     "/3" + path,
+			RawQuery: query.Encode(),
+		},
+	})
+	if err != nil {
+		logger.Error("cannot get data", "path", path, "error", err)
+		return err
+	}
+
+	if err = json.NewDecoder(body).Decode(data); err != nil {
+		logger.Error("cannot decode JSON data", "path", path, "error", err)
+		return err
+	}
+
+	return nil
+}
+
+// day parses the YYYY-MM-DD dates of the API.
+func day(input string) (time.Time, bool) {
+	if len(input) < 10 {
+		return time.Time{}, false
+	}
+	t, err := time.Parse(time.DateOnly, input[:10])
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t, true
+}
+
+func date(input string) models.AnimeDate {
+	t, ok := day(input)
+	if !ok {
+		return models.AnimeDate{}
+	}
+	return models.AnimeDate{
+		Year:  t.Year(),
+		Month: int(t.Month()),
+		Day:   t.Day(),
+	}
+}
+
+func release(input string) models.AnimeTime {
+	t, ok := day(input)
+	if !ok {
+		return models.AnimeTime{}
+	}
+	return models.AnimeTime{
+		Year:  t.Year(),
+		Month: int(t.Month()),
+		Day:   t.Day(),
+		Unix:  t.Unix(),
+	}
+}
+
+func image(path string, v *tmdbImage) models.AnimeImage {
+	if path == "" {
+		return models.AnimeImage{}
+	}
+
+	img := models.AnimeImage{
+		Image:     original + path,
+		Thumbnail: thumbnail + path,
+	}
+	if v != nil {
+		img.Height = v.Height
+		img.Width = v.Width
+	}
+	return img
+}
+
+// images maps the images, the API already sorts them by votes.
+func images(input []tmdbImage) []models.AnimeImage {
+	data := make([]models.AnimeImage, 0, len(input))
+	for i := range input {
+		if input[i].FilePath == "" {
+			continue
+		}
+		data = append(data, image(input[i].FilePath, &input[i]))
+	}
+	return data
+}
+
+// trailers keeps the trailers and teasers hosted on youtube, official ones first.
+func trailers(input tmdbVideos) []models.AnimeTrailer {
+	data := make([]models.AnimeTrailer, 0)
+	for _, v := range input.Results {
+		if !strings.EqualFold(v.Site, "youtube") || v.Key == "" {
+			continue
+		}
+		if v.Type != "Trailer" && v.Type != "Teaser" {
+			continue
+		}
+		data = append(data, models.AnimeTrailer{
+			IsOfficial: v.Official,
+			HostName:   "youtube",
+			HostKey:    v.Key,
+		})
+	}
+
+	slices.SortStableFunc(data, func(a, b models.AnimeTrailer) int {
+		switch {
+		case a.IsOfficial == b.IsOfficial:
+			return 0
+		case a.IsOfficial:
+			return -1
+		}
+		return 1
+	})
+	return data
+}
+
+func language(iso string) (models.Language, bool) {
+	for _, v := range shared.Languages {
+		if v.ISO639_1 == iso {
+			return v, true
+		}
+	}
+	return models.Language{}, false
+}
+
+// metadata returns the title and the overview in every supported language. The english
+// fallback is used when the translations have no english entry.
+func metadata(input tmdbTranslations, title, overview string) []models.MetaData {
+	var (
+		data   = make([]models.MetaData, 0)
+		filter = make(map[string]int)
+	)
+
+	for _, v := range input.Translations {
+		lang, ok := language(v.Iso6391)
+		if !ok {
+			continue
+		}
+
+		name := v.Data.Name
+		if name == "" {
+			name = v.Data.Title
+		}
+		item := models.MetaData{
+			Language: lang,
+			Title:    analyze.CleanUnicode(name),
+			OverView: analyze.CleanOverview(v.Data.Overview),
+		}
+		if item.Title == "" && item.OverView == "" {
+			continue
+		}
+
+		// a language comes once per country, keep the most complete entry.
+		if i, found := filter[lang.ISO639_1]; found {
+			if data[i].OverView == "" || (data[i].Title == "" && item.Title != "") {
+				data[i] = item
+			}
+			continue
+		}
+		filter[lang.ISO639_1] = len(data)
+		data = append(data, item)
+	}
+
+	if _, found := filter["en"]; !found && (title != "" || overview != "") {
+		lang, _ := language("en")
+		data = append(data, models.MetaData{
+			Language: lang,
+			Title:    analyze.CleanUnicode(title),
+			OverView: analyze.CleanOverview(overview),
+		})
+	}
+
+	return data
+}
+
+func companies(input []tmdbCompany) []models.AnimeCompany {
+	data := make([]models.AnimeCompany, 0, len(input))
+	for _, v := range input {
+		if v.Name == "" {
+			continue
+		}
+		data = append(data, models.AnimeCompany{
+			ID:   models.AnimeID{TMDBID: v.ID},
+			Name: strings.TrimSpace(v.Name),
+		})
+	}
+	return data
+}
+
+func genres(input []tmdbGenre) []models.AnimeGenre {
+	data := make([]models.AnimeGenre, 0, len(input))
+	for _, v := range input {
+		if name := analyze.CleanTitle(v.Name); name != "" {
+			data = append(data, models.AnimeGenre(name))
+		}
+	}
+	return data
+}
+
+func tags(input tmdbKeywords) []models.AnimeTag {
+	data := make([]models.AnimeTag, 0)
+	for _, v := range append(input.Results, input.Keywords...) {
+		if tag := analyze.CleanTag(v.Name); tag != "" {
+			data = append(data, models.AnimeTag(tag))
+		}
+	}
+	return data
+}
+
+// synonyms keeps the alternative titles used in japan and in the english speaking countries.
+func synonyms(input tmdbAlternativeTitles, known ...string) []string {
+	var data []string
+	for _, v := range append(input.Results, input.Titles...) {
+		switch v.Iso31661 {
+		case "JP", "US", "GB":
+		default:
+			continue
+		}
+		if v.Title == "" || slices.Contains(known, v.Title) {
+			continue
+		}
+		data = append(data, v.Title)
+	}
+	return analyze.CleanStrings(data)
+}
+
+func externals(homepage string, ids tmdbExternalIDs) []models.AnimeLink {
+	data := make([]models.AnimeLink, 0)
+	if homepage != "" {
+		data = append(data, models.AnimeLink{
+			Site: "official-website",
+			URL:  strings.TrimSpace(homepage),
+		})
+	}
+	if ids.ImdbID != "" {
+		data = append(data, models.AnimeLink{
+			Site: "imdb",
+			URL:  "https://www.imdb.com/title/" + ids.ImdbID,
+		})
+	}
+	if ids.WikidataID != "" {
+		data = append(data, models.AnimeLink{
+			Site: "wikidata",
+			URL:  "https://www.wikidata.org/wiki/" + ids.WikidataID,
+		})
+	}
+	return data
+}
+
+func country(input []string) string {
+	if len(input) == 0 {
+		return ""
+	}
+	return strings.ToLower(input[0])
+}
+
+// status maps the show and movie states of the API to finished, airing, upcoming or canceled.
+func status(input string) string {
+	switch strings.ToLower(input) {
+	case "ended", "released":
+		return "finished"
+	case "returning series":
+		return "airing"
+	case "canceled":
+		return "canceled"
+	case "":
+		return ""
+	}
+	return "upcoming"
+}
@@ -0,0 +1,312 @@
+package tmdb
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/internal/shared"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// animation is the id of the animation genre.
+const animation = 16
+
+func details(extra string) url.Values {
+	return url.Values{
+		"append_to_response":     {extra},
+		"include_image_language": {imageLanguages},
+	}
+}
+
+// TV returns the show with its seasons and episodes, the specials are kept as season 0.
+func TV(ctx context.Context, id int64) (*models.Anime, error) {
+	var data tmdbTV
+	err := get(ctx, "/tv/"+strconv.FormatInt(id, 10), details("images,videos,translations,external_ids,alternative_titles,keywords,content_ratings"), &data)
+	if err != nil {
+		return nil, errs.Wrap(err, "tmdb", errs.StageFetch)
+	}
+	if data.ID != id {
+		return nil, errs.Wrap(errs.ErrBadData, "tmdb", errs.StageFetch)
+	}
+
+	anime := &models.Anime{
+		Type: "TV",
+		Resources: models.AnimeResource{
+			TVDBID:   data.ExternalIDs.TvdbID,
+			TMDBID:   data.ID,
+			IMDBID:   data.ExternalIDs.ImdbID,
+			WikiData: data.ExternalIDs.WikidataID,
+		},
+		Titles: models.AnimeTitles{
+			Original: analyze.CleanStrings([]string{data.OriginalName}),
+			English:  analyze.CleanStrings([]string{data.Name}),
+			Synonyms: synonyms(data.AlternativeTitles, data.Name, data.OriginalName),
+		},
+		Description:     analyze.CleanOverview(data.Overview),
+		MetaData:        metadata(data.Translations, data.Name, data.Overview),
+		PortraitIMG:     image(data.PosterPath, nil),
+		LandscapeIMG:    image(data.BackdropPath, nil),
+		Status:          status(data.Status),
+		CountryOfOrigin: country(data.OriginCountry),
+		StartAt:         date(data.FirstAirDate),
+		EndAt:           date(data.LastAirDate),
+		Studios:         companies(data.ProductionCompanies),
+		Genres:          genres(data.Genres),
+		Tags:            tags(data.Keywords),
+		Trailers:        trailers(data.Videos),
+		External:        externals(data.Homepage, data.ExternalIDs),
+		Posters:         images(data.Images.Posters),
+		Backdrops:       images(data.Images.Backdrops),
+		Logos:           images(data.Images.Logos),
+	}
+	anime.Period = analyze.ExtractAnimePeriod(anime.StartAt)
+
+	for _, code := range []string{"US", "JP"} {
+		for _, v := range data.ContentRatings.Results {
+			if v.Iso31661 == code && v.Rating != "" {
+				anime.ContentRating = v.Rating
+				break
+			}
+		}
+		if anime.ContentRating != "" {
+			break
+		}
+	}
+
+	var (
+		runtime  int
+		absolute float32
+		now      = time.Now().Unix()
+	)
+	if len(data.EpisodeRunTime) > 0 {
+		runtime = data.EpisodeRunTime[0]
+	}
+
+	slices.SortFunc(data.Seasons, func(a, b tmdbTVSeason) int {
+		return a.SeasonNumber - b.SeasonNumber
+	})
+
+	for _, v := range data.Seasons {
+		season, episodes, err := fetchSeason(ctx, id, v.SeasonNumber)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			logger.Warn("cannot get the season", "TMDB", id, "season", v.SeasonNumber, "error", err)
+			continue
+		}
+
+		for _, x := range episodes {
+			if x.Runtime == 0 {
+				x.Runtime = runtime
+			}
+			if v.SeasonNumber > 0 {
+				absolute++
+				x.AbsoluteNumber = absolute
+			}
+			x.Aired = x.ReleaseTime.Unix > 0 && x.ReleaseTime.Unix <= now
+			anime.Episodes = append(anime.Episodes, x)
+		}
+		anime.InnerSeasons = append(anime.InnerSeasons, *season)
+	}
+
+	logger.Info("anime was added", "TMDB", id, "seasons", len(anime.InnerSeasons), "episodes", len(anime.Episodes))
+
+	return anime, nil
+}
+
+// fetchSeason returns the season of the show and its episodes, numbered within the season.
+func fetchSeason(ctx context.Context, id int64, number int) (*models.AnimeSeason, []models.AnimeEpisode, error) {
+	var data tmdbSeason
+	err := get(ctx, "/tv/"+strconv.FormatInt(id, 10)+"/season/"+strconv.Itoa(number), details("images,videos,translations,external_ids"), &data)
+	if err != nil {
+		return nil, nil, errs.Wrap(err, "tmdb", errs.StageFetch)
+	}
+
+	var (
+		episodes []models.AnimeEpisode
+		season   = &models.AnimeSeason{
+			MetaData:    metadata(data.Translations, data.Name, data.Overview),
+			PortraitIMG: image(data.PosterPath, nil),
+			Number:      data.SeasonNumber,
+			TVDBID:      data.ExternalIDs.TvdbID,
+			TMDBID:      data.ID,
+			StartAt:     date(data.AirDate),
+			Posters:     images(data.Images.Posters),
+			Trailers:    trailers(data.Videos),
+		}
+	)
+
+	lang, _ := language("en")
+	for _, v := range data.Episodes {
+		episode := models.AnimeEpisode{
+			EnTitle:       analyze.CleanUnicode(v.Name),
+			ReleaseTime:   release(v.AirDate),
+			Runtime:       v.Runtime,
+			Special:       v.SeasonNumber == 0,
+			SeasonNumber:  v.SeasonNumber,
+			Number:        float32(v.EpisodeNumber),
+			ThumbnailsIMG: image(v.StillPath, nil),
+			Resources: models.AnimeEpisodeResources{
+				TMDBID: v.ID,
+			},
+		}
+		if episode.EnTitle != "" || v.Overview != "" {
+			episode.MetaData = []models.MetaData{{
+				Language: lang,
+				Title:    episode.EnTitle,
+				OverView: analyze.CleanOverview(v.Overview),
+			}}
+		}
+		season.EndAt.Max(&models.AnimeDate{
+			Year:  episode.ReleaseTime.Year,
+			Month: episode.ReleaseTime.Month,
+			Day:   episode.ReleaseTime.Day,
+		})
+		episodes = append(episodes, episode)
+	}
+
+	return season, episodes, nil
+}
+
+// Movie returns the movie, its content rating is the american certification.
+func Movie(ctx context.Context, id int64) (*models.Anime, error) {
+	var data tmdbMovie
+	err := get(ctx, "/movie/"+strconv.FormatInt(id, 10), details("images,videos,translations,external_ids,alternative_titles,keywords,release_dates"), &data)
+	if err != nil {
+		return nil, errs.Wrap(err, "tmdb", errs.StageFetch)
+	}
+	if data.ID != id {
+		return nil, errs.Wrap(errs.ErrBadData, "tmdb", errs.StageFetch)
+	}
+
+	if data.ExternalIDs.ImdbID == "" {
+		data.ExternalIDs.ImdbID = data.ImdbID
+	}
+
+	anime := &models.Anime{
+		Type: "MOVIE",
+		Resources: models.AnimeResource{
+			TMDBID:   data.ID,
+			IMDBID:   data.ExternalIDs.ImdbID,
+			WikiData: data.ExternalIDs.WikidataID,
+		},
+		Titles: models.AnimeTitles{
+			Original: analyze.CleanStrings([]string{data.OriginalTitle}),
+			English:  analyze.CleanStrings([]string{data.Title}),
+			Synonyms: synonyms(data.AlternativeTitles, data.Title, data.OriginalTitle),
+		},
+		Description:     analyze.CleanOverview(data.Overview),
+		MetaData:        metadata(data.Translations, data.Title, data.Overview),
+		PortraitIMG:     image(data.PosterPath, nil),
+		LandscapeIMG:    image(data.BackdropPath, nil),
+		Status:          status(data.Status),
+		CountryOfOrigin: country(data.OriginCountry),
+		StartAt:         date(data.ReleaseDate),
+		EndAt:           date(data.ReleaseDate),
+		Studios:         companies(data.ProductionCompanies),
+		Genres:          genres(data.Genres),
+		Tags:            tags(data.Keywords),
+		Trailers:        trailers(data.Videos),
+		External:        externals(data.Homepage, data.ExternalIDs),
+		Posters:         images(data.Images.Posters),
+		Backdrops:       images(data.Images.Backdrops),
+		Logos:           images(data.Images.Logos),
+	}
+	anime.Period = analyze.ExtractAnimePeriod(anime.StartAt)
+
+	for _, code := range []string{"US", "JP"} {
+		for _, v := range data.ReleaseDates.Results {
+			if v.Iso31661 != code {
+				continue
+			}
+			for _, x := range v.ReleaseDates {
+				if x.Certification != "" {
+					anime.ContentRating = x.Certification
+					break
+				}
+			}
+		}
+		if anime.ContentRating != "" {
+			break
+		}
+	}
+
+	logger.Info("anime was added", "TMDB", id)
+
+	return anime, nil
+}
+
+// Search returns the id of the show, or of the movie, that best matches the title. The year
+// is the one of the first air date or of the release, zero to ignore it.
+func Search(ctx context.Context, title string, year int, movie bool) (int64, error) {
+	var (
+		path  = "/search/tv"
+		query = url.Values{
+			"query":         {title},
+			"include_adult": {"false"},
+		}
+	)
+	if movie {
+		path = "/search/movie"
+		if year != 0 {
+			query.Set("primary_release_year", strconv.Itoa(year))
+		}
+	} else if year != 0 {
+		query.Set("first_air_date_year", strconv.Itoa(year))
+	}
+
+	var data tmdbSearch
+	if err := get(ctx, path, query, &data); err != nil {
+		return 0, errs.Wrap(err, "tmdb", errs.StageSearch)
+	}
+
+	var (
+		id    int64
+		best  float64
+		clean = analyze.CleanTitle(title)
+	)
+	for _, v := range data.Results {
+		score := max(
+			shared.TextAdvancedSimilarity(clean, analyze.CleanTitle(v.Name+v.Title)),
+			shared.TextAdvancedSimilarity(clean, analyze.CleanTitle(v.OriginalName+v.OriginalTitle)),
+		)
+		if score < 80 {
+			continue
+		}
+		if slices.Contains(v.GenreIDs, animation) {
+			score += 10
+		}
+		if v.OriginalLanguage == "ja" {
+			score += 5
+		}
+		if score > best {
+			best = score
+			id = v.ID
+		}
+	}
+
+	if id == 0 {
+		return 0, errs.Wrap(errs.ErrNotFound, "tmdb", errs.StageFilter)
+	}
+
+	return id, nil
+}
+
+// Find searches the title and returns the show, or the movie, that best matches it.
+func Find(ctx context.Context, title string, year int, movie bool) (*models.Anime, error) {
+	id, err := Search(ctx, title, year, movie)
+	if err != nil {
+		return nil, err
+	}
+	if movie {
+		return Movie(ctx, id)
+	}
+	return TV(ctx, id)
+}
@@ -0,0 +1,126 @@
+package tvdb
+
+import (
+	"log/slog"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Season types a series can order its episodes by.
+const (
+	OrderAired    = "default"
+	OrderOfficial = "official"
+	OrderAbsolute = "absolute"
+	OrderDVD      = "dvd"
+)
+
+// tokens are valid for a month, they are renewed a few days before.
+const lifetime = 25 * 24 * time.Hour
+
+var (
+	key     string
+	session string
+	issued  time.Time
+	mutex   sync.Mutex
+	logger  = slog.Default().WithGroup("[TVDB]")
+)
+
+// SetKey sets the project API key used to log in and drops the current session.
+func SetKey(data string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	key = strings.TrimSpace(data)
+	session = ""
+}
+
+type tvdbResponse[T any] struct {
+	Status string `json:"status"`
+	Data   T      `json:"data"`
+	Links  struct {
+		Next string `json:"next"`
+	} `json:"links"`
+}
+
+type tvdbLogin struct {
+	Token string `json:"token"`
+}
+
+type TVDBSeasonType struct {
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+type TVDBSeason struct {
+	ID     int64          `json:"id"`
+	Number int            `json:"number"`
+	Image  string         `json:"image"`
+	Type   TVDBSeasonType `json:"type"`
+}
+
+type TVDBArtwork struct {
+	ID        int64   `json:"id"`
+	Image     string  `json:"image"`
+	Thumbnail string  `json:"thumbnail"`
+	Language  string  `json:"language"`
+	Type      int     `json:"type"`
+	Score     float64 `json:"score"`
+	Width     int     `json:"width"`
+	Height    int     `json:"height"`
+}
+
+type TVDBRemoteID struct {
+	ID         string `json:"id"`
+	Type       int    `json:"type"`
+	SourceName string `json:"sourceName"`
+}
+
+type TVDBSeries struct {
+	ID                int64   `json:"id"`
+	Name              string  `json:"name"`
+	Slug              string  `json:"slug"`
+	Image             string  `json:"image"`
+	FirstAired        string  `json:"firstAired"`
+	LastAired         string  `json:"lastAired"`
+	NextAired         string  `json:"nextAired"`
+	OriginalCountry   string  `json:"originalCountry"`
+	OriginalLanguage  string  `json:"originalLanguage"`
+	DefaultSeasonType int     `json:"defaultSeasonType"`
+	IsOrderRandomized bool    `json:"isOrderRandomized"`
+	AverageRuntime    int     `json:"averageRuntime"`
+	Overview          string  `json:"overview"`
+	Year              string  `json:"year"`
+	Score             float64 `json:"score"`
+	Status            struct {
+		ID   int    `json:"id"`
+		Name string `json:"name"`
+	} `json:"status"`
+	Aliases []struct {
+		Language string `json:"language"`
+		Name     string `json:"name"`
+	} `json:"aliases"`
+	Artworks    []TVDBArtwork    `json:"artworks"`
+	RemoteIDs   []TVDBRemoteID   `json:"remoteIds"`
+	Seasons     []TVDBSeason     `json:"seasons"`
+	SeasonTypes []TVDBSeasonType `json:"seasonTypes"`
+}
+
+type TVDBEpisode struct {
+	ID             int64  `json:"id"`
+	SeriesID       int64  `json:"seriesId"`
+	Name           string `json:"name"`
+	Overview       string `json:"overview"`
+	Aired          string `json:"aired"`
+	Runtime        int    `json:"runtime"`
+	Image          string `json:"image"`
+	Number         int    `json:"number"`
+	AbsoluteNumber int    `json:"absoluteNumber"`
+	SeasonNumber   int    `json:"seasonNumber"`
+	FinaleType     string `json:"finaleType"`
+	IsMovie        int    `json:"isMovie"`
+}
+
+type tvdbEpisodes struct {
+	Episodes []TVDBEpisode `json:"episodes"`
+}
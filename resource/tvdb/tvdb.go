@@ -0,0 +1,278 @@
+package tvdb
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+func endpoint(path string, query url.Values) *url.URL {
+	return &url.URL{
+		Scheme:   "https",
+		Host:     "api4.thetvdb.com",
+		Path:     "/v4" + path,
+		RawQuery: query.Encode(),
+	}
+}
+
+// token returns the session token, logging in again when there is none or when it is
+// about to expire.
+func token(ctx context.Context) (string, error) {
+	mutex.Lock()
+	defer mutex.Unlock()
+
+	if session != "" && time.Since(issued) < lifetime {
+		return session, nil
+	}
+	if key == "" {
+		return "", errors.New("no tvdb key was set")
+	}
+
+	payload, err := json.Marshal(map[string]string{"apikey": key})
+	if err != nil {
+		return "", err
+	}
+
+	body, err := client.Do(ctx, &client.Args{
+		Proxy:  true,
+		Method: http.MethodPost,
+		Headers: map[string]string{
+			"Content-Type": "application/json",
+			"Accept":       "application/json",
+		},
+		Endpoint: endpoint("/login", url.Values{}),
+		Body:     bytes.NewReader(payload),
+	})
+	if err != nil {
+		logger.Error("cannot log in", "error", err)
+		return "", err
+	}
+
+	var data tvdbResponse[tvdbLogin]
+	if err = json.NewDecoder(body).Decode(&data); err != nil {
+		logger.Error("cannot decode JSON data", "error", err)
+		return "", err
+	}
+	if data.Data.Token == "" {
+		return "", errs.ErrBadData
+	}
+
+	session = data.Data.Token
+	issued = time.Now()
+	logger.Info("logged in")
+
+	return session, nil
+}
+
+// expire drops the session token if it is still the given one.
+func expire(old string) {
+	mutex.Lock()
+	defer mutex.Unlock()
+	if session == old {
+		session = ""
+	}
+}
+
+// get queries the path and decodes the JSON response into data. A rejected token is
+// renewed once before giving up.
+func get[T any](ctx context.Context, path string, query url.Values, data *tvdbResponse[T]) error {
+	for i := 0; i < 2; i++ {
+		jwt, err := token(ctx)
+		if err != nil {
+			return err
+		}
+
+		body, err := client.Do(ctx, &client.Args{
+			Proxy:  true,
+			Method: http.MethodGet,
+			Headers: map[string]string{
+				"Accept":        "application/json",
+				"Authorization": "Bearer " + jwt,
+			},
+			Endpoint: endpoint(path, query),
+		})
+		if err != nil {
+			var e *errs.Error
+			if i == 0 && errors.As(err, &e) && e.Status == http.StatusUnauthorized {
+				logger.Warn("the token was rejected", "path", path)
+				expire(jwt)
+				continue
+			}
+			logger.Error("cannot get data", "path", path, "error", err)
+			return err
+		}
+
+		if err = json.NewDecoder(body).Decode(data); err != nil {
+			logger.Error("cannot decode JSON data", "path", path, "error", err)
+			return err
+		}
+		return nil
+	}
+
+	return errs.ErrBlocked
+}
+
+// Series returns the series with its seasons, season types, artworks and remote ids.
+func Series(ctx context.Context, id int64) (*TVDBSeries, error) {
+	var data tvdbResponse[TVDBSeries]
+	err := get(ctx, "/series/"+strconv.FormatInt(id, 10)+"/extended", url.Values{"short": {"true"}}, &data)
+	if err != nil {
+		return nil, errs.Wrap(err, "tvdb", errs.StageFetch)
+	}
+	if data.Data.ID != id {
+		return nil, errs.Wrap(errs.ErrBadData, "tvdb", errs.StageFetch)
+	}
+
+	logger.Info("series was added", "TVDB", id)
+
+	return &data.Data, nil
+}
+
+// Episodes returns every episode of the series in the order of the season type,
+// one of OrderAired, OrderOfficial, OrderAbsolute or OrderDVD.
+func Episodes(ctx context.Context, id int64, order string) ([]TVDBEpisode, error) {
+	var (
+		episodes []TVDBEpisode
+		path     = "/series/" + strconv.FormatInt(id, 10) + "/episodes/" + order
+	)
+
+	for page := 0; ; page++ {
+		var data tvdbResponse[tvdbEpisodes]
+		err := get(ctx, path, url.Values{"page": {strconv.Itoa(page)}}, &data)
+		if err != nil {
+			return nil, errs.Wrap(err, "tvdb", errs.StageFetch)
+		}
+
+		episodes = append(episodes, data.Data.Episodes...)
+		if data.Links.Next == "" || len(data.Data.Episodes) == 0 {
+			break
+		}
+	}
+
+	slices.SortStableFunc(episodes, func(a, b TVDBEpisode) int {
+		if a.SeasonNumber != b.SeasonNumber {
+			return a.SeasonNumber - b.SeasonNumber
+		}
+		return a.Number - b.Number
+	})
+
+	logger.Info("episodes were added", "TVDB", id, "order", order, "episodes", len(episodes))
+
+	return episodes, nil
+}
+
+// Ordering maps the absolute numbers of the episodes of a series to their aired
+// season and episode.
+type Ordering struct {
+	absolute map[int]TVDBEpisode
+	seasons  map[[2]int]TVDBEpisode
+}
+
+// Order fetches the aired episodes of the series and numbers them. The absolute season
+// type is used when the series has one, the specials are never numbered.
+func Order(ctx context.Context, id int64) (*Ordering, error) {
+	series, err := Series(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	aired, err := Episodes(ctx, id, OrderAired)
+	if err != nil {
+		return nil, err
+	}
+
+	numbers := make(map[int64]int)
+	if slices.ContainsFunc(series.SeasonTypes, func(v TVDBSeasonType) bool { return v.Type == OrderAbsolute }) {
+		absolute, err := Episodes(ctx, id, OrderAbsolute)
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return nil, err
+			}
+			logger.Warn("cannot get the absolute order", "TVDB", id, "error", err)
+		}
+		for _, v := range absolute {
+			if v.SeasonNumber > 0 && v.Number > 0 {
+				numbers[v.ID] = v.Number
+			}
+		}
+	}
+
+	x := &Ordering{
+		absolute: make(map[int]TVDBEpisode),
+		seasons:  make(map[[2]int]TVDBEpisode),
+	}
+
+	var count int
+	for _, v := range aired {
+		x.seasons[[2]int{v.SeasonNumber, v.Number}] = v
+		if v.SeasonNumber == 0 {
+			continue
+		}
+
+		count++
+		n, ok := numbers[v.ID]
+		switch {
+		case ok:
+		case v.AbsoluteNumber > 0:
+			n = v.AbsoluteNumber
+		default:
+			n = count
+		}
+		v.AbsoluteNumber = n
+		x.absolute[n] = v
+		x.seasons[[2]int{v.SeasonNumber, v.Number}] = v
+	}
+
+	return x, nil
+}
+
+// Absolute returns the episode with the absolute number.
+func (x *Ordering) Absolute(n int) (TVDBEpisode, bool) {
+	v, ok := x.absolute[n]
+	return v, ok
+}
+
+// Season returns the episode n of the aired season.
+func (x *Ordering) Season(season, n int) (TVDBEpisode, bool) {
+	v, ok := x.seasons[[2]int{season, n}]
+	return v, ok
+}
+
+// Fill sets the TVDB id, the season and the absolute number of the episodes. Specials are
+// matched by their season and number, the others by their number plus the offset, which is
+// the absolute number of the last episode before the anime.
+func (x *Ordering) Fill(episodes []models.AnimeEpisode, offset int) int {
+	var found int
+	for i := range episodes {
+		var (
+			v  TVDBEpisode
+			ok bool
+		)
+		if episodes[i].Special {
+			v, ok = x.Season(0, int(episodes[i].Number))
+		} else {
+			v, ok = x.Absolute(int(episodes[i].Number) + offset)
+		}
+		if !ok {
+			continue
+		}
+
+		episodes[i].Resources.TVDBID = v.ID
+		episodes[i].SeasonNumber = v.SeasonNumber
+		if v.SeasonNumber > 0 {
+			episodes[i].AbsoluteNumber = float32(v.AbsoluteNumber)
+		}
+		found++
+	}
+	return found
+}
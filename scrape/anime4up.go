@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -39,6 +38,10 @@ type anime4up struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("anime4up", "anime4up.lol", []string{"arabic"}, true, true, Anime4up))
+}
+
 func Anime4up(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := anime4up{
 		info:     info,
@@ -51,15 +54,20 @@ func Anime4up(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*
 	var err error
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "anime4up", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "anime4up", errs.StageFetch)
+	}
+
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "anime4up", errs.StageScrape)
 	}
 
-	return x.scrape(nodes...)
+	return result, nil
 }
 
 func (x *anime4up) query(args *client.Args) (*url.URL, error) {
@@ -133,8 +141,6 @@ func (x *anime4up) search() (*url.URL, error) {
 					return true
 				}
 
-				time.Sleep(100 * time.Millisecond)
-
 				link, err = x.query(args)
 				if err != nil {
 					return true
@@ -227,162 +233,170 @@ func (x *anime4up) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
-		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				x.log.Error("cannot load the episode page", "error", err)
-				return err
-			}
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot load the episode page", "error", err)
+			return err
+		}
 
-			doc, err := goquery.NewDocumentFromReader(body)
-			if err != nil {
-				x.log.Error("cannot parse the episode page", "error", err)
-				return err
-			}
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse the episode page", "error", err)
+			return err
+		}
 
-			var (
-				videos    []models.AnimeVideo
-				downloads []models.AnimeVideo
-			)
-			doc.Find("div.watchForm").Find("input").Each(func(_ int, s *goquery.Selection) {
-				if name, ok := s.Attr("name"); ok {
-					name = strings.TrimSpace(name)
-					if name == "wl" {
-						if value, ok := s.Attr("value"); ok {
-							body, err := base64.StdEncoding.DecodeString(value)
-							if err != nil {
-								x.log.Error("cannot decode videos body", "error", err)
-								return
-							}
+		var (
+			videos    []models.AnimeVideo
+			downloads []models.AnimeVideo
+		)
+		doc.Find("div.watchForm").Find("input").Each(func(_ int, s *goquery.Selection) {
+			if name, ok := s.Attr("name"); ok {
+				name = strings.TrimSpace(name)
+				if name == "wl" {
+					if value, ok := s.Attr("value"); ok {
+						body, err := base64.StdEncoding.DecodeString(value)
+						if err != nil {
+							x.log.Error("cannot decode videos body", "error", err)
+							return
+						}
 
-							var data anime4upWatch
-							err = json.Unmarshal(body, &data)
-							if err != nil {
-								x.log.Error("cannot parse videos body", "error", err)
-								return
-							}
+						var data anime4upWatch
+						err = json.Unmarshal(body, &data)
+						if err != nil {
+							x.log.Error("cannot parse videos body", "error", err)
+							return
+						}
 
-							for _, v := range data.FHD {
-								if v != nil {
-									if src, ok := v.(string); ok {
-										if src != "" {
-											x.log.Info("found iframe source", "src", src)
-
-											videos = append(videos, models.AnimeVideo{
-												Source:   src,
-												Type:     "sub",
-												Language: analyze.CleanLanguage("arabic"),
-												Quality:  "fhd",
-											})
-										}
+						for _, v := range data.FHD {
+							if v != nil {
+								if src, ok := v.(string); ok {
+									if src != "" {
+										x.log.Info("found iframe source", "src", src)
+
+										videos = append(videos, models.AnimeVideo{
+											Source:   src,
+											Type:     models.TrackSub,
+											Audio:    analyze.CleanLanguage("japanese"),
+											Subtitle: analyze.CleanLanguage("arabic"),
+											Quality:  models.Quality1080,
+										})
 									}
 								}
 							}
-							for _, v := range data.HD {
-								if v != nil {
-									if src, ok := v.(string); ok {
-										if src != "" {
-											x.log.Info("found iframe source", "src", src)
-
-											videos = append(videos, models.AnimeVideo{
-												Source:   src,
-												Type:     "sub",
-												Language: analyze.CleanLanguage("arabic"),
-												Quality:  "hd",
-											})
-										}
+						}
+						for _, v := range data.HD {
+							if v != nil {
+								if src, ok := v.(string); ok {
+									if src != "" {
+										x.log.Info("found iframe source", "src", src)
+
+										videos = append(videos, models.AnimeVideo{
+											Source:   src,
+											Type:     models.TrackSub,
+											Audio:    analyze.CleanLanguage("japanese"),
+											Subtitle: analyze.CleanLanguage("arabic"),
+											Quality:  models.Quality720,
+										})
 									}
 								}
 							}
-							for _, v := range data.SD {
-								if v != nil {
-									if src, ok := v.(string); ok {
-										if src != "" {
-											x.log.Info("found iframe source", "src", src)
-
-											videos = append(videos, models.AnimeVideo{
-												Source:   src,
-												Type:     "sub",
-												Language: analyze.CleanLanguage("arabic"),
-												Quality:  "sd",
-											})
-										}
+						}
+						for _, v := range data.SD {
+							if v != nil {
+								if src, ok := v.(string); ok {
+									if src != "" {
+										x.log.Info("found iframe source", "src", src)
+
+										videos = append(videos, models.AnimeVideo{
+											Source:   src,
+											Type:     models.TrackSub,
+											Audio:    analyze.CleanLanguage("japanese"),
+											Subtitle: analyze.CleanLanguage("arabic"),
+											Quality:  models.Quality480,
+										})
 									}
 								}
 							}
 						}
-						return
-					} else if name == "dl" {
-						if value, ok := s.Attr("value"); ok {
-							body, err := base64.StdEncoding.DecodeString(value)
-							if err != nil {
-								x.log.Error("cannot decode videos body", "error", err)
-								return
-							}
+					}
+					return
+				} else if name == "dl" {
+					if value, ok := s.Attr("value"); ok {
+						body, err := base64.StdEncoding.DecodeString(value)
+						if err != nil {
+							x.log.Error("cannot decode videos body", "error", err)
+							return
+						}
 
-							var data anime4upDownload
-							err = json.Unmarshal(body, &data)
-							if err != nil {
-								x.log.Error("cannot parse videos body", "error", err)
-								return
-							}
-							for _, v := range data.FHD {
-								if v != "" {
-									x.log.Info("found download url", "url", v)
-
-									downloads = append(downloads, models.AnimeVideo{
-										Source:   v,
-										Type:     "sub",
-										Language: analyze.CleanLanguage("arabic"),
-										Quality:  "fhd",
-									})
-								}
+						var data anime4upDownload
+						err = json.Unmarshal(body, &data)
+						if err != nil {
+							x.log.Error("cannot parse videos body", "error", err)
+							return
+						}
+						for _, v := range data.FHD {
+							if v != "" {
+								x.log.Info("found download url", "url", v)
+
+								downloads = append(downloads, models.AnimeVideo{
+									Source:   v,
+									Type:     models.TrackSub,
+									Audio:    analyze.CleanLanguage("japanese"),
+									Subtitle: analyze.CleanLanguage("arabic"),
+									Quality:  models.Quality1080,
+								})
 							}
-							for _, v := range data.HD {
-								if v != "" {
-									x.log.Info("found download url", "url", v)
-
-									downloads = append(downloads, models.AnimeVideo{
-										Source:   v,
-										Type:     "sub",
-										Language: analyze.CleanLanguage("arabic"),
-										Quality:  "hd",
-									})
-								}
+						}
+						for _, v := range data.HD {
+							if v != "" {
+								x.log.Info("found download url", "url", v)
+
+								downloads = append(downloads, models.AnimeVideo{
+									Source:   v,
+									Type:     models.TrackSub,
+									Audio:    analyze.CleanLanguage("japanese"),
+									Subtitle: analyze.CleanLanguage("arabic"),
+									Quality:  models.Quality720,
+								})
 							}
-							for _, v := range data.SD {
-								if v != "" {
-									x.log.Info("found download url", "url", v)
-
-									downloads = append(downloads, models.AnimeVideo{
-										Source:   v,
-										Type:     "sub",
-										Language: analyze.CleanLanguage("arabic"),
-										Quality:  "sd",
-									})
-								}
+						}
+						for _, v := range data.SD {
+							if v != "" {
+								x.log.Info("found download url", "url", v)
+
+								downloads = append(downloads, models.AnimeVideo{
+									Source:   v,
+									Type:     models.TrackSub,
+									Audio:    analyze.CleanLanguage("japanese"),
+									Subtitle: analyze.CleanLanguage("arabic"),
+									Quality:  models.Quality480,
+								})
 							}
 						}
-
-						return
 					}
+
+					return
 				}
-			})
-			result[i] = &EmbedNode{
-				Number:   v.Number,
-				Videos:   videos,
-				Download: downloads,
 			}
-			return nil
-		}(); err != nil {
-			return nil, err
+		})
+		result[i] = &EmbedNode{
+			Number:   v.Number,
+			Videos:   videos,
+			Download: downloads,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -24,6 +24,10 @@ type animeDojo struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animedojo", "animedojo.net", []string{"english"}, true, true, AnimeDojo))
+}
+
 func AnimeDojo(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeDojo{
 		info:     info,
@@ -36,20 +40,25 @@ func AnimeDojo(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]
 	var err error
 	queries, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animedojo", errs.StageSearch)
 	}
 
 	pages, err := x.filter(queries)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animedojo", errs.StageFilter)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animedojo", errs.StageFetch)
 	}
 
-	return x.scrape(nodes...)
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "animedojo", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *animeDojo) search() ([]*url.URL, error) {
@@ -302,54 +311,53 @@ func (x *animeDojo) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 	}
 
 	var result = make([]*EmbedNode, len(nodes))
-	for i, v := range nodes {
-		select {
-		case <-x.ctx.Done():
-			return nil, context.Canceled
-		default:
-			if v == nil || v.Link == nil {
-				continue
-			}
-			func() {
-				body, err := client.Do(x.ctx, &client.Args{
-					Proxy:    true,
-					Method:   http.MethodGet,
-					Endpoint: v.Link,
-				})
-				if err != nil {
-					return
-				}
+	err := each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			return err
+		}
 
-				doc, err := goquery.NewDocumentFromReader(body)
-				if err != nil {
-					return
-				}
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			return err
+		}
 
-				track := "sub"
-				if strings.Contains(v.Link.Path, "-dub-") {
-					track = "dub"
-				}
+		track := models.TrackSub
+		if strings.Contains(v.Link.Path, "-dub-") {
+			track = models.TrackDub
+		}
 
-				var videos []models.AnimeVideo
-				doc.Find("#servers-content").Find(".item").Each(func(_ int, s *goquery.Selection) {
-					href, ok := s.Find("a").Attr("href")
-					if ok && href != "" {
-						x.log.Info("found iframe source", "src", href)
-						videos = append(videos, models.AnimeVideo{
-							Source:   href,
-							Quality:  "hd",
-							Referer:  v.Link.String(),
-							Type:     track,
-							Language: analyze.CleanLanguage("english"),
-						})
-					}
+		var videos []models.AnimeVideo
+		doc.Find("#servers-content").Find(".item").Each(func(_ int, s *goquery.Selection) {
+			href, ok := s.Find("a").Attr("href")
+			if ok && href != "" {
+				x.log.Info("found iframe source", "src", href)
+				videos = append(videos, models.AnimeVideo{
+					Source:   href,
+					Quality:  models.Quality720,
+					Referer:  v.Link.String(),
+					Type:     track,
+					Audio:    analyze.CleanAudio(track, "english"),
+					Subtitle: analyze.CleanSubtitle(track, "english"),
 				})
-				result[i] = &EmbedNode{
-					Number: v.Number,
-					Videos: videos,
-				}
-			}()
+			}
+		})
+		result[i] = &EmbedNode{
+			Number: v.Number,
+			Videos: videos,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
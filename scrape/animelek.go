@@ -9,7 +9,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 
@@ -27,6 +26,10 @@ type animeLek struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animelek", "animelek.xyz", []string{"arabic"}, true, true, AnimeLek))
+}
+
 func AnimeLek(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeLek{
 		info:     info,
@@ -38,15 +41,20 @@ func AnimeLek(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*
 
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animelek", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animelek", errs.StageFetch)
 	}
 
-	return x.scrape(nodes...)
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "animelek", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *animeLek) check(link *url.URL) (bool, error) {
@@ -118,8 +126,6 @@ func (x *animeLek) search() (*url.URL, error) {
 				if err != nil {
 					return true
 				}
-				time.Sleep(100 * time.Millisecond)
-
 				found, err = x.check(link)
 				if err != nil {
 					if errors.Is(err, context.Canceled) {
@@ -213,99 +219,80 @@ func (x *animeLek) fetch(endpoint *url.URL) ([]*EpisodeNode, error) {
 
 func (x *animeLek) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 	result := make([]*EmbedNode, len(nodes))
-	for i, v := range nodes {
-		select {
-		case <-x.ctx.Done():
-			return nil, context.Canceled
-		default:
-			if v == nil || v.Link == nil {
-				continue
-			}
-			func() {
-				time.Sleep(100 * time.Millisecond)
-				body, err := client.Do(x.ctx, &client.Args{
-					Method:   http.MethodGet,
-					Endpoint: v.Link,
-				})
-				if err != nil {
-					x.log.Error("cannot load the episode page", "error", err)
-					return
-				}
+	err := each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot load the episode page", "error", err)
+			return err
+		}
 
-				doc, err := goquery.NewDocumentFromReader(body)
-				if err != nil {
-					x.log.Error("cannot parse the episode page", "error", err)
-					return
-				}
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse the episode page", "error", err)
+			return err
+		}
+
+		tab := doc.Find(".tab-content")
+		if tab == nil {
+			return nil
+		}
 
-				tab := doc.Find(".tab-content")
-				if tab == nil {
-					return
+		var (
+			videos    []models.AnimeVideo
+			downloads []models.AnimeVideo
+		)
+
+		tab.Find("#watch").Find("li").Each(func(_ int, s *goquery.Selection) {
+			if src, ok := s.Find("a").Attr("data-ep-url"); ok {
+				if src != "" {
+					quality := s.Find("small").Text()
+
+					x.log.Info("found iframe source", "src", src)
+
+					videos = append(videos, models.AnimeVideo{
+						Source:   src,
+						Type:     models.TrackSub,
+						Quality:  analyze.CleanQuality(quality),
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+					})
 				}
+			}
+		})
 
-				var (
-					videos    []models.AnimeVideo
-					downloads []models.AnimeVideo
-				)
-
-				tab.Find("#watch").Find("li").Each(func(_ int, s *goquery.Selection) {
-					if src, ok := s.Find("a").Attr("data-ep-url"); ok {
-						if src != "" {
-							quality := strings.ToLower(strings.TrimSpace(s.Find("small").Text()))
-							if strings.Contains(quality, "hd") {
-								quality = "hd"
-							}
-							if strings.Contains(quality, "fhd") {
-								quality = "fhd"
-							}
-							if strings.Contains(quality, "sd") || strings.Contains(quality, "ld") {
-								quality = "sd"
-							}
-
-							x.log.Info("found iframe source", "src", src)
-
-							videos = append(videos, models.AnimeVideo{
-								Source:   src,
-								Type:     "sub",
-								Quality:  quality,
-								Language: analyze.CleanLanguage("arabic"),
-							})
-						}
-					}
-				})
-
-				tab.Find("#downloads").Find("li").Each(func(_ int, s *goquery.Selection) {
-					if src, ok := s.Find("a").Attr("href"); ok {
-						if src != "" {
-							quality := strings.ToLower(s.Find("small").Text())
-							if strings.Contains(quality, "hd") {
-								quality = "hd"
-							}
-							if strings.Contains(quality, "fhd") {
-								quality = "fhd"
-							}
-							if strings.Contains(quality, "sd") || strings.Contains(quality, "ld") {
-								quality = "sd"
-							}
-
-							x.log.Info("found download url", "url", src)
-
-							downloads = append(downloads, models.AnimeVideo{
-								Source:   src,
-								Type:     "sub",
-								Quality:  quality,
-								Language: analyze.CleanLanguage("arabic"),
-							})
-						}
-					}
-				})
-				result[i] = &EmbedNode{
-					Number:   v.Number,
-					Videos:   videos,
-					Download: downloads,
+		tab.Find("#downloads").Find("li").Each(func(_ int, s *goquery.Selection) {
+			if src, ok := s.Find("a").Attr("href"); ok {
+				if src != "" {
+					quality := s.Find("small").Text()
+
+					x.log.Info("found download url", "url", src)
+
+					downloads = append(downloads, models.AnimeVideo{
+						Source:   src,
+						Type:     models.TrackSub,
+						Quality:  analyze.CleanQuality(quality),
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+					})
 				}
-			}()
+			}
+		})
+		result[i] = &EmbedNode{
+			Number:   v.Number,
+			Videos:   videos,
+			Download: downloads,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
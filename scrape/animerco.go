@@ -11,7 +11,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -29,6 +28,10 @@ type animeRco struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animerco", "ww3.animerco.org", []string{"arabic"}, true, true, AnimeRco))
+}
+
 func AnimeRco(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeRco{
 		info:     info,
@@ -40,15 +43,20 @@ func AnimeRco(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*
 
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animerco", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animerco", errs.StageFetch)
+	}
+
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "animerco", errs.StageScrape)
 	}
 
-	return x.scrape(nodes...)
+	return result, nil
 }
 
 func (x *animeRco) confirm(page *url.URL) (*url.URL, error) {
@@ -114,7 +122,6 @@ func (x *animeRco) check(page *url.URL) (*url.URL, error) {
 				return true
 			}
 
-			time.Sleep(100 * time.Millisecond)
 			link, err = x.confirm(page)
 			if err != nil {
 				if errors.Is(err, context.Canceled) {
@@ -202,7 +209,6 @@ func (x *animeRco) search() (*url.URL, error) {
 					return true
 				}
 
-				time.Sleep(100 * time.Millisecond)
 				link, err = x.check(page)
 				if err != nil {
 					if errors.Is(err, context.Canceled) {
@@ -358,8 +364,6 @@ func (x *animeRco) video(doc *goquery.Document, link *url.URL) ([]models.AnimeVi
 		num, o3 := a.Attr("data-nume")
 		if o1 && o2 && o3 {
 			args.Body = strings.NewReader(fmt.Sprintf("action=player_ajax&post=%s&nume=%s&type=%s", pst, num, typ))
-			time.Sleep(100 * time.Millisecond)
-
 			body, err := client.Do(x.ctx, args)
 			if err != nil {
 				x.log.Error("cannot get the iframe data", "error", err)
@@ -393,9 +397,10 @@ func (x *animeRco) video(doc *goquery.Document, link *url.URL) ([]models.AnimeVi
 					x.log.Info("found iframe source", "src", src)
 					videos = append(videos, models.AnimeVideo{
 						Source:   strings.TrimSpace(src),
-						Type:     "sub",
-						Language: analyze.CleanLanguage("arabic"),
-						Quality:  "hd",
+						Type:     models.TrackSub,
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+						Quality:  models.Quality720,
 					})
 				}
 			}
@@ -422,8 +427,6 @@ func (x *animeRco) download(doc *goquery.Document) ([]models.AnimeVideo, error)
 				return true
 			}
 
-			time.Sleep(100 * time.Millisecond)
-
 			body, err := client.Do(x.ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
@@ -454,9 +457,10 @@ func (x *animeRco) download(doc *goquery.Document) ([]models.AnimeVideo, error)
 
 					downloads = append(downloads, models.AnimeVideo{
 						Source:   strings.TrimSpace(src),
-						Type:     "sub",
-						Language: analyze.CleanLanguage("arabic"),
-						Quality:  "fhd",
+						Type:     models.TrackSub,
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+						Quality:  models.Quality1080,
 					})
 				}
 			}
@@ -472,21 +476,21 @@ func (x *animeRco) download(doc *goquery.Document) ([]models.AnimeVideo, error)
 
 func (x *animeRco) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 	result := make([]*EmbedNode, len(nodes))
-	for i, v := range nodes {
+	err := each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
 		}
-		time.Sleep(100 * time.Millisecond)
-
 		data, err := x.extract(v)
 		if err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
-			}
-			continue
+			return err
 		}
 
 		result[i] = data
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -10,7 +10,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -33,6 +32,10 @@ type animeSaturn struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animesaturn", "www.animesaturn.mx", []string{"italian"}, true, true, AnimeSaturn))
+}
+
 func AnimeSaturn(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeSaturn{
 		info:     info,
@@ -44,25 +47,30 @@ func AnimeSaturn(ctx context.Context, info *models.AnimeInfo, episodes []int) (*
 
 	pages, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animesaturn", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animesaturn", errs.StageFetch)
 	}
 
 	nodes, err = x.clean(nodes)
 	if nodes == nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animesaturn", errs.StageFilter)
 	}
 
 	nodes, err = x.filter(nodes)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animesaturn", errs.StageFilter)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "animesaturn", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *animeSaturn) check(args *client.Args) (*url.URL, error) {
@@ -254,7 +262,6 @@ func (x *animeSaturn) clean(nodes []*EpisodeNode) ([]*EpisodeNode, error) {
 			continue
 		}
 		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
 			body, err := client.Do(x.ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
@@ -286,10 +293,10 @@ func (x *animeSaturn) clean(nodes []*EpisodeNode) ([]*EpisodeNode, error) {
 				return err
 			}
 
-			track := "sub"
+			track := models.TrackSub
 			title := card.Find("h3").Text()
 			if !strings.Contains(strings.ToLower(title), " sub ita") {
-				track = "dub"
+				track = models.TrackDub
 			}
 
 			result = append(result, &EpisodeNode{
@@ -319,8 +326,6 @@ func (x *animeSaturn) filter(nodes []*EpisodeNode) ([]*EpisodeNode, error) {
 			continue
 		}
 		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
-
 			body, err := client.Do(x.ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
@@ -376,54 +381,51 @@ func (x *animeSaturn) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot get the iframe page", "error", err)
+			return err
 		}
-		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
 
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				x.log.Error("cannot get the iframe page", "error", err)
-				return err
-			}
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse the iframe page", "error", err)
+			return err
+		}
 
-			doc, err := goquery.NewDocumentFromReader(body)
-			if err != nil {
-				x.log.Error("cannot parse the iframe page", "error", err)
-				return err
-			}
+		if src, ok := doc.Find(".embed-container").Find("iframe").Attr("src"); ok {
+			if src != "" {
+				x.log.Info("found iframe source", "src", src)
 
-			if src, ok := doc.Find(".embed-container").Find("iframe").Attr("src"); ok {
-				if src != "" {
-					x.log.Info("found iframe source", "src", src)
-
-					result[i] = &EmbedNode{
-						Number: v.Number,
-						Videos: []models.AnimeVideo{
-							{
-								Source:   src,
-								Type:     v.Type,
-								Quality:  "hd",
-								Language: analyze.CleanLanguage("italian"),
-							},
+				result[i] = &EmbedNode{
+					Number: v.Number,
+					Videos: []models.AnimeVideo{
+						{
+							Source:   src,
+							Type:     v.Type,
+							Quality:  models.Quality720,
+							Audio:    analyze.CleanAudio(v.Type, "italian"),
+							Subtitle: analyze.CleanSubtitle(v.Type, "italian"),
 						},
-					}
-
-					return nil
+					},
 				}
-			}
-			return errs.ErrNoData
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
+
+				return nil
 			}
 		}
+		return errs.ErrNoData
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -78,6 +78,10 @@ type animeSlayer struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animeslayer", "anslayer.com", []string{"arabic"}, true, true, AnimeSlayer))
+}
+
 func AnimeSlayer(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeSlayer{
 		info:     info,
@@ -96,20 +100,25 @@ func AnimeSlayer(ctx context.Context, info *models.AnimeInfo, episodes []int) (*
 
 	ids, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeslayer", errs.StageSearch)
 	}
 
 	page, err := x.check(ids)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeslayer", errs.StageFilter)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeslayer", errs.StageFetch)
 	}
 
-	return x.scrape(nodes...)
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "animeslayer", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *animeSlayer) search() ([]string, error) {
@@ -387,108 +396,107 @@ func (x *animeSlayer) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
 		}
-		if err = func() error {
-			args := &client.Args{
-				Proxy:    true,
-				Method:   http.MethodPost,
-				Endpoint: v.Link,
-				Body:     strings.NewReader(v.Link.RawQuery),
-				Headers:  x.headers,
-			}
-			v.Link.RawQuery = ""
-
-			body, err := client.Do(context.Background(), args)
-			if err != nil {
-				x.log.Error("cannot get the episode data", "error", err)
-				return err
-			}
-
-			var item animeSlayerData
-			err = json.NewDecoder(body).Decode(&item)
-			if err != nil {
-				x.log.Error("cannot parse the episode data", "error", err)
-				return err
-			}
+		args := &client.Args{
+			Proxy:    true,
+			Method:   http.MethodPost,
+			Endpoint: v.Link,
+			Body:     strings.NewReader(v.Link.RawQuery),
+			Headers:  x.headers,
+		}
+		v.Link.RawQuery = ""
 
-			for _, y := range item.Response.Data {
-				for _, z := range y.EpisodeUrls {
-					var sources []string
-					if strings.Contains(z.EpisodeURL, "v-qs.php") {
-						data, err := url.ParseQuery(z.EpisodeURL)
-						if err != nil {
-							x.log.Error("cannot parse the episode server", "error", err)
-							continue
-						}
-						data.Add("inf", "")
-						endpoint := &url.URL{
-							Scheme: v.Link.Scheme,
-							Host:   v.Link.Host,
-							Path:   "/anime/public/v-qs.php",
-						}
+		body, err := client.Do(x.ctx, args)
+		if err != nil {
+			x.log.Error("cannot get the episode data", "error", err)
+			return err
+		}
 
-						values, err := x.extract(endpoint, data)
-						if err != nil {
-							if errors.Is(err, context.Canceled) {
-								return err
-							}
-							continue
-						}
+		var item animeSlayerData
+		err = json.NewDecoder(body).Decode(&item)
+		if err != nil {
+			x.log.Error("cannot parse the episode data", "error", err)
+			return err
+		}
 
-						sources = append(sources, values...)
+		for _, y := range item.Response.Data {
+			for _, z := range y.EpisodeUrls {
+				var sources []string
+				if strings.Contains(z.EpisodeURL, "v-qs.php") {
+					data, err := url.ParseQuery(z.EpisodeURL)
+					if err != nil {
+						x.log.Error("cannot parse the episode server", "error", err)
+						continue
+					}
+					data.Add("inf", "")
+					endpoint := &url.URL{
+						Scheme: v.Link.Scheme,
+						Host:   v.Link.Host,
+						Path:   "/anime/public/v-qs.php",
 					}
 
-					if strings.Contains(z.EpisodeURL, "api/f2") {
-						data, err := url.ParseQuery(z.EpisodeURL)
-						if err != nil {
-							x.log.Error("cannot parse the episode server", "error", err)
-							continue
-						}
-						data.Add("inf", "")
-						endpoint := &url.URL{
-							Scheme: v.Link.Scheme,
-							Host:   v.Link.Host,
-							Path:   "/la/public/api/fw",
+					values, err := x.extract(endpoint, data)
+					if err != nil {
+						if errors.Is(err, context.Canceled) {
+							return err
 						}
+						continue
+					}
 
-						values, err := x.extract(endpoint, data)
-						if err != nil {
-							if errors.Is(err, context.Canceled) {
-								return err
-							}
-							continue
-						}
+					sources = append(sources, values...)
+				}
 
-						sources = append(sources, values...)
+				if strings.Contains(z.EpisodeURL, "api/f2") {
+					data, err := url.ParseQuery(z.EpisodeURL)
+					if err != nil {
+						x.log.Error("cannot parse the episode server", "error", err)
+						continue
 					}
-
-					var videos []models.AnimeVideo
-					for _, src := range sources {
-						x.log.Info("found iframe source", "src", src)
-						videos = append(videos, models.AnimeVideo{
-							Source:   src,
-							Type:     "sub",
-							Quality:  "sd",
-							Language: analyze.CleanLanguage("arabic"),
-						})
+					data.Add("inf", "")
+					endpoint := &url.URL{
+						Scheme: v.Link.Scheme,
+						Host:   v.Link.Host,
+						Path:   "/la/public/api/fw",
 					}
-					result[i] = &EmbedNode{
-						Number: v.Number,
-						Videos: videos,
+
+					values, err := x.extract(endpoint, data)
+					if err != nil {
+						if errors.Is(err, context.Canceled) {
+							return err
+						}
+						continue
 					}
-					return nil
+
+					sources = append(sources, values...)
 				}
-			}
 
-			return errs.ErrNoData
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
+				var videos []models.AnimeVideo
+				for _, src := range sources {
+					x.log.Info("found iframe source", "src", src)
+					videos = append(videos, models.AnimeVideo{
+						Source:   src,
+						Type:     models.TrackSub,
+						Quality:  models.Quality480,
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+					})
+				}
+				result[i] = &EmbedNode{
+					Number: v.Number,
+					Videos: videos,
+				}
+				return nil
 			}
 		}
+
+		return errs.ErrNoData
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
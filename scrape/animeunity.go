@@ -11,7 +11,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -45,6 +44,11 @@ type animeUnity struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("animeunity", "www.animeunity.to", []string{"italian"}, true, true, AnimeUnity))
+	client.SetJar(nil, "animeunity.to")
+}
+
 func AnimeUnity(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := animeUnity{
 		info:     info,
@@ -56,20 +60,25 @@ func AnimeUnity(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[
 
 	err := x.refresh()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeunity", errs.StageSearch)
 	}
 
 	pages, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeunity", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "animeunity", errs.StageFetch)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "animeunity", errs.StageScrape)
+	}
+
+	return result, nil
 }
 func (x *animeUnity) refresh() error {
 	endpoint := &url.URL{
@@ -95,7 +104,7 @@ func (x *animeUnity) refresh() error {
 		sr string
 	)
 
-	for _, v := range args.Cookies() {
+	for _, v := range client.Cookies(endpoint) {
 		if strings.Contains(strings.ToUpper(v.Name), "XSRF-TOKEN") {
 			xr = v.Value
 		}
@@ -141,7 +150,6 @@ func (x *animeUnity) refresh() error {
 		"X-Requested-With": "XMLHttpRequest",
 		"Referer":          endpoint.Scheme + "://" + endpoint.Host + "/",
 		"Origin":           endpoint.Scheme + "://" + endpoint.Host,
-		"Cookie":           strconv.Quote(fmt.Sprintf("X-XSRF-TOKEN=%s; animeunity_session=%s", xr, sr)),
 		"X-CSRF-TOKEN":     cr,
 		"X-XSRF-TOKEN":     xr,
 	}
@@ -203,7 +211,6 @@ func (x *animeUnity) fetch(endpoints []*url.URL) ([]*EpisodeNode, error) {
 			continue
 		}
 		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
 			body, err := client.Do(x.ctx, &client.Args{
 				Method:   http.MethodGet,
 				Endpoint: v,
@@ -235,9 +242,9 @@ func (x *animeUnity) fetch(endpoints []*url.URL) ([]*EpisodeNode, error) {
 				return err
 			}
 
-			track := "sub"
+			track := models.TrackSub
 			if strings.Contains(v.String(), "-ita") {
-				track = "dub"
+				track = models.TrackDub
 			}
 
 			for _, y := range code {
@@ -291,53 +298,51 @@ func (x *animeUnity) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Headers:  x.headers,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot get iframe data", "error", err)
+			return err
 		}
-		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Headers:  x.headers,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				x.log.Error("cannot get iframe data", "error", err)
-				return err
-			}
 
-			buf := new(bytes.Buffer)
-			buf.ReadFrom(body)
+		buf := new(bytes.Buffer)
+		buf.ReadFrom(body)
 
-			link, err := url.Parse(strings.TrimSpace(buf.String()))
-			if err != nil {
-				x.log.Error("cannot parse iframe url", "error", err)
-				return err
-			}
+		link, err := url.Parse(strings.TrimSpace(buf.String()))
+		if err != nil {
+			x.log.Error("cannot parse iframe url", "error", err)
+			return err
+		}
 
-			x.log.Info("found iframe source", "src", link.String())
-
-			result[i] = &EmbedNode{
-				Number: v.Number,
-				Videos: []models.AnimeVideo{
-					{
-						Source:   link.Scheme + "://" + link.Host + link.Path,
-						Referer:  v.Link.Scheme + "://" + v.Link.Host,
-						Type:     v.Type,
-						Quality:  "fhd",
-						Language: analyze.CleanLanguage("italian"),
-					},
+		x.log.Info("found iframe source", "src", link.String())
+
+		result[i] = &EmbedNode{
+			Number: v.Number,
+			Videos: []models.AnimeVideo{
+				{
+					Source:   link.Scheme + "://" + link.Host + link.Path,
+					Referer:  v.Link.Scheme + "://" + v.Link.Host,
+					Type:     v.Type,
+					Quality:  models.Quality1080,
+					Audio:    analyze.CleanAudio(v.Type, "italian"),
+					Subtitle: analyze.CleanSubtitle(v.Type, "italian"),
 				},
-			}
-
-			return nil
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
-			}
+			},
 		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -8,7 +8,6 @@ import (
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -26,6 +25,10 @@ type gogoAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("gogoanime", "anitaku.pe", []string{"english"}, true, true, GogoAnime))
+}
+
 func GogoAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := gogoAnime{
 		info:     info,
@@ -41,20 +44,25 @@ func GogoAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]
 
 	queries, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "gogoanime", errs.StageSearch)
 	}
 
 	pages, err := x.filter(queries)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "gogoanime", errs.StageFilter)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "gogoanime", errs.StageFetch)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "gogoanime", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *gogoAnime) search() ([]*url.URL, error) {
@@ -211,7 +219,6 @@ func (x *gogoAnime) fetch(endpoints []*url.URL) ([]*EpisodeNode, error) {
 			continue
 		}
 		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
 			body, err := client.Do(x.ctx, &client.Args{
 				Proxy:    true,
 				Method:   http.MethodGet,
@@ -229,7 +236,7 @@ func (x *gogoAnime) fetch(endpoints []*url.URL) ([]*EpisodeNode, error) {
 				return err
 			}
 
-			var track string
+			var track models.Track
 			doc.Find("li").Each(func(i int, s *goquery.Selection) {
 				href, ok := s.Find("a").Attr("href")
 				if !ok {
@@ -241,9 +248,9 @@ func (x *gogoAnime) fetch(endpoints []*url.URL) ([]*EpisodeNode, error) {
 					return
 				}
 
-				track = "sub"
+				track = models.TrackSub
 				if strings.Contains(strings.ToLower(s.Find(".cate").Text()), "dub") {
-					track = "dub"
+					track = models.TrackDub
 				}
 
 				if x.isMovie {
@@ -293,57 +300,55 @@ func (x *gogoAnime) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot get episode page", "error", err)
+			return err
 		}
-		if err = func() error {
-			time.Sleep(100 * time.Millisecond)
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				x.log.Error("cannot get episode page", "error", err)
-				return err
-			}
-
-			doc, err := goquery.NewDocumentFromReader(body)
-			if err != nil {
-				x.log.Error("cannot parse episode page", "error", err)
-				return err
-			}
 
-			var videos []models.AnimeVideo
-			doc.Find(".anime_video_body").Find("li").Each(func(_ int, s *goquery.Selection) {
-				if source, ok := s.Find("a").Attr("data-video"); ok {
-					if source != "" {
-						x.log.Info("found iframe source", "src", source)
-						videos = append(videos, models.AnimeVideo{
-							Source:   source,
-							Type:     v.Type,
-							Quality:  "hd",
-							Language: analyze.CleanLanguage("english"),
-						})
-					}
-				}
-			})
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse episode page", "error", err)
+			return err
+		}
 
-			if len(videos) != 0 {
-				result[i] = &EmbedNode{
-					Number: v.Number,
-					Videos: videos,
+		var videos []models.AnimeVideo
+		doc.Find(".anime_video_body").Find("li").Each(func(_ int, s *goquery.Selection) {
+			if source, ok := s.Find("a").Attr("data-video"); ok {
+				if source != "" {
+					x.log.Info("found iframe source", "src", source)
+					videos = append(videos, models.AnimeVideo{
+						Source:   source,
+						Type:     v.Type,
+						Quality:  models.Quality720,
+						Audio:    analyze.CleanAudio(v.Type, "english"),
+						Subtitle: analyze.CleanSubtitle(v.Type, "english"),
+					})
 				}
-				return nil
 			}
+		})
 
-			return errs.ErrNotFound
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
+		if len(videos) != 0 {
+			result[i] = &EmbedNode{
+				Number: v.Number,
+				Videos: videos,
 			}
+			return nil
 		}
+
+		return errs.ErrNotFound
+	})
+	if err != nil {
+		return nil, err
 	}
 	if len(result) == 0 {
 		return nil, errs.ErrNotFound
@@ -44,6 +44,10 @@ type jkAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("jkanime", "jkanime.net", []string{"spanish"}, true, true, JKAnime))
+}
+
 func JKAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := jkAnime{
 		info:     info,
@@ -55,20 +59,25 @@ func JKAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*E
 
 	queries, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "jkanime", errs.StageSearch)
 	}
 
 	pages, err := x.filter(queries)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "jkanime", errs.StageFilter)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "jkanime", errs.StageFetch)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "jkanime", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *jkAnime) search() ([]string, error) {
@@ -228,12 +237,12 @@ func (x *jkAnime) fetch(page *url.URL) ([]*EpisodeNode, error) {
 		return nil, errs.ErrNotFound
 	}
 
-	last, err := strconv.Atoi(pages[len(pages)-1])
+	last, err := strconv.Atoi(strings.TrimSpace(pages[len(pages)-1]))
 	if err != nil {
 		return nil, err
 	}
 	if last == 0 {
-		last, err = strconv.Atoi(pages[0])
+		last, err = strconv.Atoi(strings.TrimSpace(pages[0]))
 		if err != nil {
 			return nil, err
 		}
@@ -245,7 +254,7 @@ func (x *jkAnime) fetch(page *url.URL) ([]*EpisodeNode, error) {
 
 	var nodes []*EpisodeNode
 	for _, v := range x.episodes {
-		for z := range last {
+		for z := range last + 1 {
 			if v == z {
 				path := page.Path + "/" + strconv.Itoa(v) + "/"
 				x.log.Info("found episode url", "ep", v, "link", path)
@@ -269,67 +278,66 @@ func (x *jkAnime) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			return err
 		}
-		if err = func() error {
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				return err
-			}
 
-			doc, err := goquery.NewDocumentFromReader(body)
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			return err
+		}
+
+		match := jkExp.FindStringSubmatch(doc.Text())
+		if len(match) > 1 {
+			txt := "[{" + match[1] + "}]"
+			var iframe []jkAnimeFrame
+			err = json.Unmarshal([]byte(txt), &iframe)
 			if err != nil {
+				x.log.Error("cannot parse the iframe", "error", err)
 				return err
 			}
 
-			match := jkExp.FindStringSubmatch(doc.Text())
-			if len(match) > 1 {
-				txt := "[{" + match[1] + "}]"
-				var iframe []jkAnimeFrame
-				err = json.Unmarshal([]byte(txt), &iframe)
-				if err != nil {
-					x.log.Error("cannot parse the iframe", "error", err)
-					return err
+			var videos []models.AnimeVideo
+			for _, z := range iframe {
+				code, err := base64.StdEncoding.DecodeString(z.Remote)
+				if err != nil || len(code) == 0 {
+					continue
 				}
 
-				var videos []models.AnimeVideo
-				for _, z := range iframe {
-					code, err := base64.StdEncoding.DecodeString(z.Remote)
-					if err != nil || len(code) == 0 {
-						continue
-					}
+				x.log.Info("found iframe source", "src", string(code))
 
-					x.log.Info("found iframe source", "src", string(code))
-
-					videos = append(videos, models.AnimeVideo{
-						Source:   string(code),
-						Type:     "sub",
-						Quality:  "fhd",
-						Language: analyze.CleanLanguage("es"),
-					})
-				}
-				if len(videos) == 0 {
-					return errs.ErrNotFound
-				}
-
-				result[i] = &EmbedNode{
-					Number: v.Number,
-					Videos: videos,
-				}
+				videos = append(videos, models.AnimeVideo{
+					Source:   string(code),
+					Type:     models.TrackSub,
+					Quality:  models.Quality1080,
+					Audio:    analyze.CleanLanguage("japanese"),
+					Subtitle: analyze.CleanLanguage("spanish"),
+				})
+			}
+			if len(videos) == 0 {
+				return errs.ErrNotFound
 			}
 
-			return errs.ErrNotFound
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
+			result[i] = &EmbedNode{
+				Number: v.Number,
+				Videos: videos,
 			}
 		}
+
+		return errs.ErrNotFound
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
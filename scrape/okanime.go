@@ -3,14 +3,12 @@ package scrape
 import (
 	"context"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"log/slog"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -45,6 +43,10 @@ type okAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("okanime", "okanime.tv", []string{"arabic"}, true, true, OkAnime))
+}
+
 func OkAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := okAnime{
 		info:     info,
@@ -56,15 +58,20 @@ func OkAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*E
 
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "okanime", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "okanime", errs.StageFetch)
+	}
+
+	result, err := x.scrape(nodes...)
+	if err != nil {
+		return nil, errs.Wrap(err, "okanime", errs.StageScrape)
 	}
 
-	return x.scrape(nodes...)
+	return result, nil
 }
 
 func (x *okAnime) search() (*url.URL, error) {
@@ -194,127 +201,102 @@ func (x *okAnime) scrape(nodes ...*EpisodeNode) (*[]*EmbedNode, error) {
 	var (
 		err    error
 		result = make([]*EmbedNode, len(nodes))
-		args   = &client.Args{
+	)
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+		args := &client.Args{
 			Method: http.MethodGet,
 			Headers: map[string]string{
 				"sec-fetch-site":   "same-origin",
 				"x-requested-with": "XMLHttpRequest",
 			},
 		}
-	)
-	for i, v := range nodes {
-		select {
-		case <-x.ctx.Done():
-			return nil, context.Canceled
-		default:
-			if v == nil || v.Link == nil {
-				continue
-			}
-
-			time.Sleep(100 * time.Millisecond)
-			if err = func() error {
-				body, err := client.Do(x.ctx, &client.Args{
-					Proxy:    true,
-					Method:   http.MethodGet,
-					Endpoint: v.Link,
-				})
-				if err != nil {
-					x.log.Error("cannot get the episode page", "error", err)
-					return err
-				}
-
-				doc, err := goquery.NewDocumentFromReader(body)
-				if err != nil {
-					x.log.Error("cannot parse the episode page", "error", err)
-					return err
-				}
-
-				var videos []models.AnimeVideo
-				doc.Find("#myTabContent").Find("#watch").Find(".servers-list").Each(func(_ int, s *goquery.Selection) {
-					if href, ok := s.Attr("data-href"); ok {
-						if href != "" {
-							args.Endpoint, err = url.Parse(v.Link.Scheme + "://" + v.Link.Host + href)
-							if err != nil {
-								return
-							}
-
-							time.Sleep(100 * time.Millisecond)
-							page, err := client.Do(x.ctx, args)
-							if err != nil {
-								x.log.Error("cannot get the episode page", "error", err)
-								return
-							}
 
-							var result okAnimeItem
-							err = json.NewDecoder(page).Decode(&result)
-							if err != nil {
-								return
-							}
-							if result.Data.Attributes.URL != "" {
-								quality := strings.ToLower(strings.TrimSpace(s.Find("small").Text()))
-								if strings.Contains(quality, "hd") {
-									quality = "hd"
-								}
-								if strings.Contains(quality, "fhd") {
-									quality = "fhd"
-								}
-								if strings.Contains(quality, "sd") || strings.Contains(quality, "ld") {
-									quality = "sd"
-								}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot get the episode page", "error", err)
+			return err
+		}
 
-								x.log.Info("found iframe source", "src", result.Data.Attributes.URL)
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse the episode page", "error", err)
+			return err
+		}
 
-								videos = append(videos, models.AnimeVideo{
-									Source:   strings.TrimSpace(result.Data.Attributes.URL),
-									Referer:  v.Link.Scheme + "://" + v.Link.Host,
-									Quality:  quality,
-									Type:     "sub",
-									Language: analyze.CleanLanguage("arabic"),
-								})
-							}
-						}
+		var videos []models.AnimeVideo
+		doc.Find("#myTabContent").Find("#watch").Find(".servers-list").Each(func(_ int, s *goquery.Selection) {
+			if href, ok := s.Attr("data-href"); ok {
+				if href != "" {
+					args.Endpoint, err = url.Parse(v.Link.Scheme + "://" + v.Link.Host + href)
+					if err != nil {
+						return
 					}
-				})
-
-				var downloads []models.AnimeVideo
-				doc.Find("#myTabContent").Find("#download").Find(".webinars-inner-wrap").Find("a").Each(func(_ int, s *goquery.Selection) {
-					if href, ok := s.Attr("href"); ok {
-						if href != "" {
-							quality := strings.ToLower(strings.TrimSpace(s.Find("small").Text()))
-							if strings.Contains(quality, "hd") {
-								quality = "hd"
-							}
-							if strings.Contains(quality, "fhd") {
-								quality = "fhd"
-							}
-							if strings.Contains(quality, "sd") || strings.Contains(quality, "ld") {
-								quality = "sd"
-							}
-
-							x.log.Info("found download url", "url", strings.TrimSpace(href))
 
-							downloads = append(downloads, models.AnimeVideo{
-								Source:   strings.TrimSpace(href),
-								Quality:  quality,
-								Type:     "sub",
-								Language: analyze.CleanLanguage("arabic"),
-							})
-						}
+					page, err := client.Do(x.ctx, args)
+					if err != nil {
+						x.log.Error("cannot get the episode page", "error", err)
+						return
 					}
-				})
 
-				result[i] = &EmbedNode{
-					Number:   v.Number,
-					Videos:   videos,
-					Download: downloads,
+					var result okAnimeItem
+					err = json.NewDecoder(page).Decode(&result)
+					if err != nil {
+						return
+					}
+					if result.Data.Attributes.URL != "" {
+						quality := s.Find("small").Text()
+
+						x.log.Info("found iframe source", "src", result.Data.Attributes.URL)
+
+						videos = append(videos, models.AnimeVideo{
+							Source:   strings.TrimSpace(result.Data.Attributes.URL),
+							Referer:  v.Link.Scheme + "://" + v.Link.Host,
+							Quality:  analyze.CleanQuality(quality),
+							Type:     models.TrackSub,
+							Audio:    analyze.CleanLanguage("japanese"),
+							Subtitle: analyze.CleanLanguage("arabic"),
+						})
+					}
 				}
-				return nil
-			}(); err != nil {
-				if errors.Is(err, context.Canceled) {
-					return nil, err
+			}
+		})
+
+		var downloads []models.AnimeVideo
+		doc.Find("#myTabContent").Find("#download").Find(".webinars-inner-wrap").Find("a").Each(func(_ int, s *goquery.Selection) {
+			if href, ok := s.Attr("href"); ok {
+				if href != "" {
+					quality := s.Find("small").Text()
+
+					x.log.Info("found download url", "url", strings.TrimSpace(href))
+
+					downloads = append(downloads, models.AnimeVideo{
+						Source:   strings.TrimSpace(href),
+						Quality:  analyze.CleanQuality(quality),
+						Type:     models.TrackSub,
+						Audio:    analyze.CleanLanguage("japanese"),
+						Subtitle: analyze.CleanLanguage("arabic"),
+					})
 				}
 			}
+		})
+
+		result[i] = &EmbedNode{
+			Number:   v.Number,
+			Videos:   videos,
+			Download: downloads,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -0,0 +1,189 @@
+package scrape
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// Scraper is a single streaming site that can turn an anime info and a list of episodes into embed nodes.
+type Scraper interface {
+	Name() string
+	Host() string
+	Languages() []models.Language
+	SupportsMovie() bool
+	SupportsTV() bool
+	Scrape(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error)
+}
+
+// ScrapeFunc is the signature shared by every scraper of this package.
+type ScrapeFunc func(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error)
+
+type provider struct {
+	name      string
+	host      string
+	languages []models.Language
+	movie     bool
+	tv        bool
+	fn        ScrapeFunc
+}
+
+func (x *provider) Name() string {
+	return x.name
+}
+
+func (x *provider) Host() string {
+	return x.host
+}
+
+func (x *provider) Languages() []models.Language {
+	return x.languages
+}
+
+func (x *provider) SupportsMovie() bool {
+	return x.movie
+}
+
+func (x *provider) SupportsTV() bool {
+	return x.tv
+}
+
+func (x *provider) Scrape(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
+	return x.fn(ctx, info, episodes)
+}
+
+// NewScraper wraps a scrape function into a Scraper that can be registered.
+func NewScraper(name, host string, languages []string, movie, tv bool, fn ScrapeFunc) Scraper {
+	x := &provider{
+		name:  strings.ToLower(name),
+		host:  host,
+		movie: movie,
+		tv:    tv,
+		fn:    fn,
+	}
+	for _, v := range languages {
+		x.languages = append(x.languages, analyze.CleanLanguage(v))
+	}
+
+	return x
+}
+
+var (
+	registry = make(map[string]Scraper)
+	disabled = make(map[string]struct{})
+	rmutex   sync.RWMutex
+)
+
+// Register adds the scraper to the registry, replacing any scraper with the same name.
+func Register(s Scraper) {
+	if s == nil || s.Name() == "" {
+		return
+	}
+
+	rmutex.Lock()
+	defer rmutex.Unlock()
+	registry[strings.ToLower(s.Name())] = s
+}
+
+// Lookup returns the registered scraper with the given name.
+func Lookup(name string) (Scraper, bool) {
+	rmutex.RLock()
+	defer rmutex.RUnlock()
+
+	s, ok := registry[strings.ToLower(name)]
+	return s, ok
+}
+
+// Enable turns the given scrapers back on, with no names it enables all of them.
+func Enable(names ...string) {
+	rmutex.Lock()
+	defer rmutex.Unlock()
+
+	if len(names) == 0 {
+		clear(disabled)
+		return
+	}
+	for _, v := range names {
+		delete(disabled, strings.ToLower(v))
+	}
+}
+
+// Disable turns off the given scrapers, with no names it disables all of them.
+func Disable(names ...string) {
+	rmutex.Lock()
+	defer rmutex.Unlock()
+
+	if len(names) == 0 {
+		for k := range registry {
+			disabled[k] = struct{}{}
+		}
+		return
+	}
+	for _, v := range names {
+		disabled[strings.ToLower(v)] = struct{}{}
+	}
+}
+
+// Enabled reports whether the scraper with the given name is registered and enabled.
+func Enabled(name string) bool {
+	rmutex.RLock()
+	defer rmutex.RUnlock()
+
+	name = strings.ToLower(name)
+	if _, ok := registry[name]; !ok {
+		return false
+	}
+	_, ok := disabled[name]
+	return !ok
+}
+
+// Names returns the names of all registered scrapers sorted alphabetically.
+func Names() []string {
+	rmutex.RLock()
+	defer rmutex.RUnlock()
+
+	names := make([]string, 0, len(registry))
+	for k := range registry {
+		names = append(names, k)
+	}
+	slices.Sort(names)
+
+	return names
+}
+
+// supports reports whether the scraper can handle the given anime type, an empty type matches.
+func supports(s Scraper, kind string) bool {
+	switch kind {
+	case "":
+		return true
+	case "movie":
+		return s.SupportsMovie()
+	}
+	return s.SupportsTV()
+}
+
+// Scrapers returns the enabled scrapers that can handle the given anime type, sorted by name.
+// An empty type returns every enabled scraper.
+func Scrapers(kind string) []Scraper {
+	rmutex.RLock()
+	defer rmutex.RUnlock()
+
+	var data []Scraper
+	for k, v := range registry {
+		if _, ok := disabled[k]; ok {
+			continue
+		}
+		if supports(v, kind) {
+			data = append(data, v)
+		}
+	}
+	slices.SortFunc(data, func(a, b Scraper) int {
+		return strings.Compare(a.Name(), b.Name())
+	})
+
+	return data
+}
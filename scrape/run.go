@@ -0,0 +1,278 @@
+package scrape
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+)
+
+// Options controls which providers RunAll uses and how hard it pushes them.
+type Options struct {
+	// Providers restricts the run to the given scraper names, empty means every enabled scraper.
+	Providers []string
+	// Parallel caps how many providers run at the same time, zero means no cap.
+	Parallel int
+	// Concurrency is the number of episode pages a provider may scrape at the same time, default is 1.
+	Concurrency map[string]int
+	// Timeout bounds the time spent on a single provider, zero means no bound.
+	Timeout time.Duration
+	// Episodes overrides the episodes of each provider, when it is set the providers
+	// missing from it or with no episodes are skipped.
+	Episodes map[string][]int
+}
+
+// SourceVideo is a video together with the provider that found it.
+type SourceVideo struct {
+	Provider string            `json:"Provider"`
+	Video    models.AnimeVideo `json:"Video"`
+}
+
+// EpisodeResult holds every video found for one episode across all providers.
+type EpisodeResult struct {
+	Number   int           `json:"Number"`
+	Videos   []SourceVideo `json:"Videos"`
+	Download []SourceVideo `json:"Download"`
+}
+
+// Result is the merged output of RunAll, episodes are sorted by number.
+type Result struct {
+	Episodes []*EpisodeResult `json:"Episodes"`
+	Errors   map[string]error `json:"-"`
+	mutex    sync.Mutex
+	index    map[int]*EpisodeResult
+}
+
+func (x *Result) add(name string, nodes *[]*EmbedNode) {
+	if nodes == nil {
+		return
+	}
+
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	for _, v := range *nodes {
+		if v == nil {
+			continue
+		}
+		ep, ok := x.index[v.Number]
+		if !ok {
+			ep = &EpisodeResult{Number: v.Number}
+			x.index[v.Number] = ep
+			x.Episodes = append(x.Episodes, ep)
+		}
+		for _, y := range v.Videos {
+			ep.Videos = append(ep.Videos, SourceVideo{Provider: name, Video: y})
+		}
+		for _, y := range v.Download {
+			ep.Download = append(ep.Download, SourceVideo{Provider: name, Video: y})
+		}
+	}
+}
+
+func (x *Result) fail(name string, err error) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+	x.Errors[name] = err
+}
+
+// Merge drops the videos and downloads of each episode that several providers found and ranks
+// the rest, see analyze.MergeAnimeVideos. A shared video is credited to the first provider that found it.
+func (x *Result) Merge(rank *analyze.VideoRank) {
+	x.mutex.Lock()
+	defer x.mutex.Unlock()
+
+	for _, ep := range x.Episodes {
+		ep.Videos = merge(rank, ep.Videos)
+		ep.Download = merge(rank, ep.Download)
+	}
+}
+
+func merge(rank *analyze.VideoRank, list []SourceVideo) []SourceVideo {
+	var (
+		providers = make(map[string]string)
+		videos    = make([]models.AnimeVideo, 0, len(list))
+	)
+	for _, v := range list {
+		key := analyze.ExtractSourceKey(v.Video.Source)
+		if _, ok := providers[key]; !ok {
+			providers[key] = v.Provider
+		}
+		videos = append(videos, v.Video)
+	}
+
+	var result []SourceVideo
+	for _, v := range analyze.MergeAnimeVideos(rank, videos) {
+		result = append(result, SourceVideo{
+			Provider: providers[analyze.ExtractSourceKey(v.Source)],
+			Video:    v,
+		})
+	}
+	return result
+}
+
+// RunAll scrapes the episodes of the anime with every selected provider concurrently and merges
+// the embed nodes by episode number. A provider that fails or times out is recorded in Result.Errors
+// and does not stop the others.
+func RunAll(ctx context.Context, info *models.AnimeInfo, episodes []int, opts *Options) (*Result, error) {
+	if info == nil {
+		return nil, errs.ErrBadData
+	}
+	if opts == nil {
+		opts = new(Options)
+	}
+
+	var scrapers []Scraper
+	if len(opts.Providers) > 0 {
+		for _, v := range opts.Providers {
+			s, ok := Lookup(v)
+			switch {
+			case !ok:
+				runLog.Warn("unknown provider", "name", v)
+			case !Enabled(v):
+				runLog.Warn("provider is disabled", "name", v)
+			case !supports(s, info.Type):
+				runLog.Warn("provider does not support the type", "name", v, "type", info.Type)
+			default:
+				scrapers = append(scrapers, s)
+			}
+		}
+	} else {
+		scrapers = Scrapers(info.Type)
+	}
+	if opts.Episodes != nil {
+		scrapers = slices.DeleteFunc(scrapers, func(s Scraper) bool {
+			return len(opts.Episodes[s.Name()]) == 0
+		})
+	}
+	if len(scrapers) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	var (
+		wg     sync.WaitGroup
+		slots  chan struct{}
+		result = &Result{
+			Errors: make(map[string]error),
+			index:  make(map[int]*EpisodeResult),
+		}
+	)
+	if opts.Parallel > 0 {
+		slots = make(chan struct{}, opts.Parallel)
+	}
+
+	for _, s := range scrapers {
+		wg.Add(1)
+		go func(s Scraper) {
+			defer wg.Done()
+			if slots != nil {
+				select {
+				case slots <- struct{}{}:
+					defer func() { <-slots }()
+				case <-ctx.Done():
+					result.fail(s.Name(), ctx.Err())
+					return
+				}
+			}
+
+			pctx := ctx
+			if opts.Timeout > 0 {
+				var cancel context.CancelFunc
+				pctx, cancel = context.WithTimeout(ctx, opts.Timeout)
+				defer cancel()
+			}
+
+			list := episodes
+			if opts.Episodes != nil {
+				list = opts.Episodes[s.Name()]
+			}
+
+			start := time.Now()
+			err := run(pctx, s, info, list, opts.Concurrency[s.Name()], result)
+			if err != nil {
+				runLog.Warn("provider failed", "name", s.Name(), "took", time.Since(start), "error", err)
+				result.fail(s.Name(), err)
+				return
+			}
+			runLog.Info("provider finished", "name", s.Name(), "took", time.Since(start))
+		}(s)
+	}
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	slices.SortFunc(result.Episodes, func(a, b *EpisodeResult) int {
+		return a.Number - b.Number
+	})
+	if len(result.Episodes) == 0 {
+		return result, errs.ErrNotFound
+	}
+
+	return result, nil
+}
+
+// run scrapes the episodes with the provider, which fetches at most limit episode pages at once.
+func run(ctx context.Context, s Scraper, info *models.AnimeInfo, episodes []int, limit int, result *Result) error {
+	nodes, err := s.Scrape(withConcurrency(ctx, limit), info, episodes)
+	if err != nil {
+		return err
+	}
+	result.add(s.Name(), nodes)
+	return nil
+}
+
+type concurrencyKey struct{}
+
+// withConcurrency returns a context that lets the scrapers fetch up to n episode pages at
+// the same time, one when n is below one.
+func withConcurrency(ctx context.Context, n int) context.Context {
+	return context.WithValue(ctx, concurrencyKey{}, max(n, 1))
+}
+
+// concurrency returns how many episode pages may be fetched at the same time, see withConcurrency.
+func concurrency(ctx context.Context) int {
+	if n, ok := ctx.Value(concurrencyKey{}).(int); ok {
+		return n
+	}
+	return 1
+}
+
+// each calls fn for the indexes from 0 to n with at most concurrency(ctx) calls in flight.
+// Errors of single calls are left to fn, only a context that is canceled or past its deadline
+// stops the loop and its error is returned, so a timeout is not mistaken for a partial success.
+func each(ctx context.Context, n int, fn func(i int) error) error {
+	var (
+		wg    sync.WaitGroup
+		once  sync.Once
+		fail  error
+		slots = make(chan struct{}, concurrency(ctx))
+	)
+	for i := 0; i < n; i++ {
+		select {
+		case <-ctx.Done():
+			wg.Wait()
+			return ctx.Err()
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer func() {
+				<-slots
+				wg.Done()
+			}()
+			if err := fn(i); errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+				once.Do(func() { fail = err })
+			}
+		}(i)
+	}
+	wg.Wait()
+
+	return fail
+}
@@ -0,0 +1,61 @@
+package scrape
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEach(t *testing.T) {
+	var (
+		calls    atomic.Int32
+		inflight atomic.Int32
+		peak     atomic.Int32
+	)
+	err := each(withConcurrency(context.Background(), 3), 10, func(i int) error {
+		calls.Add(1)
+		n := inflight.Add(1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		time.Sleep(5 * time.Millisecond)
+		inflight.Add(-1)
+		if i == 4 {
+			return errors.New("episode page not found")
+		}
+		return nil
+	})
+
+	assert.NoError(t, err, "the errors of single calls are left to fn")
+	assert.EqualValues(t, 10, calls.Load())
+	assert.LessOrEqual(t, peak.Load(), int32(3))
+}
+
+func TestEachContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := each(ctx, 5, func(int) error { return nil })
+	assert.ErrorIs(t, err, context.Canceled)
+
+	ctx, cancel = context.WithTimeout(context.Background(), time.Millisecond)
+	defer cancel()
+	<-ctx.Done()
+	err = each(ctx, 5, func(int) error { return nil })
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// a call that ran into the deadline of the provider
+	err = each(context.Background(), 3, func(i int) error {
+		if i == 1 {
+			return context.DeadlineExceeded
+		}
+		return nil
+	})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -53,6 +53,10 @@ type sAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("sanime", "app.sanime.net", []string{"arabic"}, true, true, SAnime))
+}
+
 func SAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := sAnime{
 		info:     info,
@@ -64,15 +68,20 @@ func SAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*Em
 
 	pages, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "sanime", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(pages)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "sanime", errs.StageFetch)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "sanime", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *sAnime) search() ([]string, error) {
@@ -256,58 +265,58 @@ func (x *sAnime) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
 		if v == nil || v.Link == nil {
-			continue
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Proxy:    true,
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot get the episode page", "error", err)
+			return err
 		}
-		if err = func() error {
-			body, err := client.Do(x.ctx, &client.Args{
-				Proxy:    true,
-				Method:   http.MethodGet,
-				Endpoint: v.Link,
-			})
-			if err != nil {
-				x.log.Error("cannot get the episode page", "error", err)
-				return err
-			}
 
-			var video sAnimeVideo
-			err = json.NewDecoder(body).Decode(&video)
-			if err != nil {
-				x.log.Error("cannot parse the episode data", "error", err)
-				return err
-			}
+		var video sAnimeVideo
+		err = json.NewDecoder(body).Decode(&video)
+		if err != nil {
+			x.log.Error("cannot parse the episode data", "error", err)
+			return err
+		}
 
-			var videos []models.AnimeVideo
-			if video.Hd != "" {
-				x.log.Info("found iframe source", "src", video.Hd)
-				videos = append(videos, models.AnimeVideo{
-					Source:   video.Hd,
-					Type:     "sub",
-					Quality:  "hd",
-					Language: analyze.CleanLanguage("arabic"),
-				})
-			}
+		var videos []models.AnimeVideo
+		if video.Hd != "" {
+			x.log.Info("found iframe source", "src", video.Hd)
+			videos = append(videos, models.AnimeVideo{
+				Source:   video.Hd,
+				Type:     models.TrackSub,
+				Quality:  models.Quality720,
+				Audio:    analyze.CleanLanguage("japanese"),
+				Subtitle: analyze.CleanLanguage("arabic"),
+			})
+		}
 
-			if video.Sd != "" {
-				x.log.Info("found iframe source", "src", video.Sd)
-				videos = append(videos, models.AnimeVideo{
-					Source:   video.Sd,
-					Type:     "sub",
-					Quality:  "sd",
-					Language: analyze.CleanLanguage("arabic"),
-				})
-			}
-			result[i] = &EmbedNode{
-				Number: v.Number,
-				Videos: videos,
-			}
-			return nil
-		}(); err != nil {
-			if errors.Is(err, context.Canceled) {
-				return nil, err
-			}
+		if video.Sd != "" {
+			x.log.Info("found iframe source", "src", video.Sd)
+			videos = append(videos, models.AnimeVideo{
+				Source:   video.Sd,
+				Type:     models.TrackSub,
+				Quality:  models.Quality480,
+				Audio:    analyze.CleanLanguage("japanese"),
+				Subtitle: analyze.CleanLanguage("arabic"),
+			})
 		}
+		result[i] = &EmbedNode{
+			Number: v.Number,
+			Videos: videos,
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	if len(result) == 0 {
@@ -32,6 +32,10 @@ type shahidAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("shahidanime", "shahiid-anime.net", []string{"arabic"}, true, true, ShahidAnime))
+}
+
 func ShahidAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := shahidAnime{
 		info:     info,
@@ -43,15 +47,20 @@ func ShahidAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*
 
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "shahidanime", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "shahidanime", errs.StageFetch)
 	}
 
-	return x.scrape(nodes)
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "shahidanime", errs.StageScrape)
+	}
+
+	return result, nil
 }
 
 func (x *shahidAnime) search() (*url.URL, error) {
@@ -59,7 +68,7 @@ func (x *shahidAnime) search() (*url.URL, error) {
 		Scheme:   "https",
 		Host:     "shahiid-anime.net",
 		Path:     "/",
-		RawQuery: "s=" + analyze.CleanQuery(x.info.Title),
+		RawQuery: "s=" + analyze.CleanQuery(x.info.Query),
 	}
 
 	body, err := client.Do(x.ctx, &client.Args{
@@ -88,7 +97,7 @@ func (x *shahidAnime) search() (*url.URL, error) {
 			if !strings.Contains(title, "فيلم") {
 				return true
 			}
-			if shared.TextAdvancedSimilarity(analyze.CleanTitle(analyze.ExtractEngChars(title)), x.info.Title) < 90 {
+			if shared.TextAdvancedSimilarity(analyze.CleanTitle(analyze.ExtractEngChars(title)), x.info.Query) < 90 {
 				return true
 			}
 		}
@@ -105,7 +114,7 @@ func (x *shahidAnime) search() (*url.URL, error) {
 
 		if !x.isMovie {
 			if strings.Contains(href, "/seasons/") {
-				if analyze.CleanTitle(analyze.ExtractEngChars(title)) == x.info.Title {
+				if analyze.CleanTitle(analyze.ExtractEngChars(title)) == x.info.Query {
 					found = true
 					return false
 				}
@@ -184,7 +193,7 @@ func (x *shahidAnime) check(endpoint *url.URL) (*url.URL, error) {
 			return true
 		}
 
-		if shared.TextAdvancedSimilarity(analyze.CleanTitle(analyze.ExtractEngChars(s.Find("h2").Text())), x.info.Title) > 90 {
+		if shared.TextAdvancedSimilarity(analyze.CleanTitle(analyze.ExtractEngChars(s.Find("h2").Text())), x.info.Query) > 90 {
 			found = true
 			return false
 		}
@@ -343,17 +352,16 @@ func (x *shahidAnime) movie(link *url.URL) (*EmbedNode, error) {
 			return
 		}
 
-		if !strings.Contains(source, "http") {
-			source = "http" + source
-		}
+		source = analyze.CleanSource(source)
 
 		x.log.Info("found iframe source", "src", source)
 
 		videos = append(videos, models.AnimeVideo{
 			Source:   source,
-			Type:     "sub",
-			Quality:  "hd",
-			Language: analyze.CleanLanguage("arabic"),
+			Type:     models.TrackSub,
+			Quality:  models.Quality720,
+			Audio:    analyze.CleanLanguage("japanese"),
+			Subtitle: analyze.CleanLanguage("arabic"),
 		})
 
 	})
@@ -365,9 +373,10 @@ func (x *shahidAnime) movie(link *url.URL) (*EmbedNode, error) {
 				x.log.Info("found download url", "url", href)
 				downloads = append(downloads, models.AnimeVideo{
 					Source:   href,
-					Type:     "sub",
-					Quality:  "hd",
-					Language: analyze.CleanLanguage("arabic"),
+					Type:     models.TrackSub,
+					Quality:  models.Quality720,
+					Audio:    analyze.CleanLanguage("japanese"),
+					Subtitle: analyze.CleanLanguage("arabic"),
 				})
 			}
 		}
@@ -427,9 +436,10 @@ func (x *shahidAnime) tv(ep *EpisodeNode) (*EmbedNode, error) {
 
 		videos = append(videos, models.AnimeVideo{
 			Source:   source,
-			Type:     "sub",
-			Quality:  "hd",
-			Language: analyze.CleanLanguage("arabic"),
+			Type:     models.TrackSub,
+			Quality:  models.Quality720,
+			Audio:    analyze.CleanLanguage("japanese"),
+			Subtitle: analyze.CleanLanguage("arabic"),
 		})
 	})
 
@@ -461,30 +471,22 @@ func (x *shahidAnime) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
-		select {
-		case <-x.ctx.Done():
-			return nil, context.Canceled
-		default:
-			if v == nil || v.Link == nil {
-				continue
-			}
-			if x.isMovie {
-				result[i], err = x.movie(v.Link)
-				if err != nil {
-					if errors.Is(err, context.Canceled) {
-						return nil, err
-					}
-				}
-			} else {
-				result[i], err = x.tv(v)
-				if err != nil {
-					if errors.Is(err, context.Canceled) {
-						return nil, err
-					}
-				}
-			}
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+
+		var err error
+		if x.isMovie {
+			result[i], err = x.movie(v.Link)
+		} else {
+			result[i], err = x.tv(v)
 		}
+		return err
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
@@ -512,10 +514,7 @@ func (x *shahidAnime) video(endpoint *url.URL) (string, error) {
 	}
 
 	if src, ok := doc.Find("iframe").Attr("src"); ok {
-		if !strings.Contains(src, "http") {
-			src = "http" + src
-		}
-		return src, nil
+		return analyze.CleanSource(src), nil
 	}
 
 	return "", errs.ErrNotFound
@@ -549,9 +548,10 @@ func (x *shahidAnime) download(endpoint *url.URL) []models.AnimeVideo {
 				x.log.Info("found download url", "url", href)
 				downloads = append(downloads, models.AnimeVideo{
 					Source:   href,
-					Type:     "sub",
-					Quality:  "hd",
-					Language: analyze.CleanLanguage("arabic"),
+					Type:     models.TrackSub,
+					Quality:  models.Quality720,
+					Audio:    analyze.CleanLanguage("japanese"),
+					Subtitle: analyze.CleanLanguage("arabic"),
 				})
 			}
 		}
@@ -20,6 +20,7 @@ var (
 	okAnimeLog     = slog.Default().WithGroup("[OK-ANIME]")
 	shahidAnimeLog = slog.Default().WithGroup("[SHAHID-ANIME]")
 	sAnimeLog      = slog.Default().WithGroup("[S-ANIME]")
+	runLog         = slog.Default().WithGroup("[SCRAPE]")
 )
 
 type EmbedNode struct {
@@ -30,6 +31,6 @@ type EmbedNode struct {
 
 type EpisodeNode struct {
 	Number int
-	Type   string
+	Type   models.Track
 	Link   *url.URL
 }
@@ -12,7 +12,6 @@ import (
 	"regexp"
 	"strconv"
 	"strings"
-	"time"
 
 	"github.com/PuerkitoBio/goquery"
 	"github.com/anicine/anicine-scraper/client"
@@ -41,6 +40,10 @@ type witAnime struct {
 	ctx      context.Context
 }
 
+func init() {
+	Register(NewScraper("witanime", "witanime.one", []string{"arabic"}, true, true, WitAnime))
+}
+
 func WitAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*EmbedNode, error) {
 	x := witAnime{
 		info:     info,
@@ -52,15 +55,20 @@ func WitAnime(ctx context.Context, info *models.AnimeInfo, episodes []int) (*[]*
 
 	page, err := x.search()
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "witanime", errs.StageSearch)
 	}
 
 	nodes, err := x.fetch(page)
 	if err != nil {
-		return nil, err
+		return nil, errs.Wrap(err, "witanime", errs.StageFetch)
+	}
+
+	result, err := x.scrape(nodes)
+	if err != nil {
+		return nil, errs.Wrap(err, "witanime", errs.StageScrape)
 	}
 
-	return x.scrape(nodes)
+	return result, nil
 }
 
 func (x *witAnime) check(args *client.Args) error {
@@ -306,9 +314,10 @@ func (x *witAnime) video(text string) []models.AnimeVideo {
 
 		result = append(result, models.AnimeVideo{
 			Source:   source,
-			Type:     "sub",
-			Quality:  "hd",
-			Language: language,
+			Type:     models.TrackSub,
+			Quality:  models.Quality720,
+			Audio:    analyze.CleanLanguage("japanese"),
+			Subtitle: language,
 		})
 	}
 
@@ -349,9 +358,10 @@ func (x *witAnime) download(text string) []models.AnimeVideo {
 		}
 		result = append(result, models.AnimeVideo{
 			Source:   source,
-			Type:     "sub",
-			Quality:  "hd",
-			Language: language,
+			Type:     models.TrackSub,
+			Quality:  models.Quality720,
+			Audio:    analyze.CleanLanguage("japanese"),
+			Subtitle: language,
 		})
 	}
 
@@ -363,47 +373,39 @@ func (x *witAnime) scrape(nodes []*EpisodeNode) (*[]*EmbedNode, error) {
 		err    error
 		result = make([]*EmbedNode, len(nodes))
 	)
-	for i, v := range nodes {
-		select {
-		case <-x.ctx.Done():
-			return nil, context.Canceled
-		default:
-			if v == nil || v.Link == nil {
-				continue
-			}
-			if err = func() error {
-				time.Sleep(100 * time.Millisecond)
-				body, err := client.Do(x.ctx, &client.Args{
-					Method:   http.MethodGet,
-					Endpoint: v.Link,
-				})
-				if err != nil {
-					x.log.Error("cannot load the episode page", "error", err)
-					return err
-				}
+	err = each(x.ctx, len(nodes), func(i int) error {
+		v := nodes[i]
+		if v == nil || v.Link == nil {
+			return nil
+		}
+		body, err := client.Do(x.ctx, &client.Args{
+			Method:   http.MethodGet,
+			Endpoint: v.Link,
+		})
+		if err != nil {
+			x.log.Error("cannot load the episode page", "error", err)
+			return err
+		}
 
-				doc, err := goquery.NewDocumentFromReader(body)
-				if err != nil {
-					x.log.Error("cannot parse the episode page", "error", err)
-					return err
-				}
+		doc, err := goquery.NewDocumentFromReader(body)
+		if err != nil {
+			x.log.Error("cannot parse the episode page", "error", err)
+			return err
+		}
 
-				txt := doc.Text()
-				video := x.video(txt)
-				download := x.download(txt)
+		txt := doc.Text()
+		video := x.video(txt)
+		download := x.download(txt)
 
-				result[i] = &EmbedNode{
-					Number:   v.Number,
-					Videos:   video,
-					Download: download,
-				}
-				return nil
-			}(); err != nil {
-				if errors.Is(err, context.Canceled) {
-					return nil, err
-				}
-			}
+		result[i] = &EmbedNode{
+			Number:   v.Number,
+			Videos:   video,
+			Download: download,
 		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
 	}
 
 	return &result, nil
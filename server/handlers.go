@@ -0,0 +1,205 @@
+package server
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/mapping"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/resource/anithms"
+	"github.com/anicine/anicine-scraper/resource/funart"
+	"github.com/anicine/anicine-scraper/resource/mal"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+// info builds the anime info from the path and the query, for example
+// /anime/52991/ids?title=Sousou+no+Frieren&type=tv&start=2023-09-29 or /anime/52991/ids
+func info(r *http.Request) (*models.AnimeInfo, error) {
+	id, err := strconv.Atoi(r.PathValue("mal"))
+	if err != nil || id <= 0 {
+		return nil, fmt.Errorf("%w: invalid mal id", errs.ErrBadData)
+	}
+
+	query := r.URL.Query()
+	sd, err := analyze.ExtractAnimeDate(query.Get("start"))
+	if err != nil {
+		return nil, err
+	}
+	ed, err := analyze.ExtractAnimeDate(query.Get("end"))
+	if err != nil {
+		return nil, err
+	}
+	kind := strings.ToLower(query.Get("type"))
+
+	// without a title the anime is read from MyAnimeList, the parameters still override it
+	title := strings.TrimSpace(query.Get("title"))
+	if title == "" {
+		data, err := mal.Info(r.Context(), id)
+		if err != nil {
+			return nil, err
+		}
+		if kind != "" {
+			data.Type = kind
+		}
+		if query.Get("start") != "" {
+			data.SD = sd
+		}
+		if query.Get("end") != "" {
+			data.ED = ed
+		}
+		return data, nil
+	}
+
+	if kind == "" {
+		kind = "tv"
+	}
+
+	return &models.AnimeInfo{
+		Title: title,
+		Query: analyze.CleanTitle(title),
+		Type:  kind,
+		MalID: id,
+		SD:    sd,
+		ED:    ed,
+	}, nil
+}
+
+func embeds(w http.ResponseWriter, r *http.Request) {
+	data, err := info(r)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	n, err := strconv.Atoi(r.PathValue("n"))
+	if err != nil {
+		fail(w, fmt.Errorf("%w: invalid episode number", errs.ErrBadData))
+		return
+	}
+
+	opts := &scrape.Options{Timeout: 2 * time.Minute}
+	if v := r.URL.Query().Get("providers"); v != "" {
+		opts.Providers = strings.Split(v, ",")
+	}
+
+	result, err := scrape.RunAll(r.Context(), data, []int{n}, opts)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	reply(w, http.StatusOK, result)
+}
+
+func ids(w http.ResponseWriter, r *http.Request) {
+	data, err := info(r)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	query := r.URL.Query()
+	livechart, _ := strconv.Atoi(query.Get("livechart"))
+	resource, err := mapping.All(r.Context(), data, livechart, query.Get("notifymoe"))
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	reply(w, http.StatusOK, resource)
+}
+
+func themes(w http.ResponseWriter, r *http.Request) {
+	data, err := info(r)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	var (
+		query   = r.URL.Query()
+		romanji = query.Get("romanji")
+		period  = analyze.ExtractAnimePeriod(data.SD)
+	)
+	if romanji == "" {
+		romanji = data.Title
+	}
+
+	result, err := anithms.Fetch(r.Context(), data.Title, query.Get("english"), romanji, data.SD.Year, period.Season)
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	reply(w, http.StatusOK, result)
+}
+
+// art returns the fanart of the anime, the tvdb or the tmdb parameters pick the show or
+// the movie, without them the ids are mapped from the MAL id.
+func art(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	tvdb, _ := strconv.ParseInt(query.Get("tvdb"), 10, 64)
+	tmdb, _ := strconv.ParseInt(query.Get("tmdb"), 10, 64)
+	movie := tmdb != 0 && tvdb == 0
+
+	if tvdb == 0 && tmdb == 0 {
+		data, err := info(r)
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		resource, err := mapping.All(r.Context(), data, 0, "")
+		if err != nil {
+			fail(w, err)
+			return
+		}
+		tvdb, tmdb = resource.TVDBID, resource.TMDBID
+		movie = data.Type == "movie" && tmdb != 0 || tvdb == 0
+	}
+
+	var (
+		result any
+		err    error
+	)
+	switch {
+	case movie && tmdb != 0:
+		result, err = funart.Movie(r.Context(), int(tmdb))
+	case tvdb != 0:
+		result, err = funart.TV(r.Context(), int(tvdb))
+	default:
+		err = fmt.Errorf("%w: no tvdb or tmdb id for the anime", errs.ErrNotFound)
+	}
+	if err != nil {
+		fail(w, err)
+		return
+	}
+
+	reply(w, http.StatusOK, result)
+}
+
+func providers(w http.ResponseWriter, _ *http.Request) {
+	type provider struct {
+		Name      string            `json:"Name"`
+		Host      string            `json:"Host"`
+		Languages []models.Language `json:"Languages"`
+		Enabled   bool              `json:"Enabled"`
+	}
+
+	var data []provider
+	for _, v := range scrape.Names() {
+		s, _ := scrape.Lookup(v)
+		data = append(data, provider{
+			Name:      s.Name(),
+			Host:      s.Host(),
+			Languages: s.Languages(),
+			Enabled:   scrape.Enabled(v),
+		})
+	}
+
+	reply(w, http.StatusOK, data)
+}
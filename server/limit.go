@@ -0,0 +1,118 @@
+package server
+
+import (
+	"crypto/subtle"
+	"net"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// bucket counts the requests of one client, it refills at the rate of the guard.
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+// guard protects the endpoints that scrape the sites: it checks the token when one is
+// set, limits the requests of every client and caps the scrapes running at once.
+type guard struct {
+	token   string
+	rate    float64
+	burst   float64
+	slots   chan struct{}
+	buckets map[string]*bucket
+	mutex   sync.Mutex
+}
+
+// newGuard allows perMinute requests to each client, and burst of them at once, while at
+// most concurrency requests run together. A zero rate disables the client limit.
+func newGuard(token string, perMinute float64, burst, concurrency int) *guard {
+	return &guard{
+		token:   token,
+		rate:    perMinute / 60,
+		burst:   float64(max(burst, 1)),
+		slots:   make(chan struct{}, max(concurrency, 1)),
+		buckets: make(map[string]*bucket),
+	}
+}
+
+// allow takes a token of the client and returns how long it has to wait when none is left.
+func (g *guard) allow(client string) time.Duration {
+	if g.rate <= 0 {
+		return 0
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	now := time.Now()
+	b, ok := g.buckets[client]
+	if !ok {
+		b = &bucket{tokens: g.burst, last: now}
+		g.buckets[client] = b
+	}
+
+	b.tokens = min(g.burst, b.tokens+now.Sub(b.last).Seconds()*g.rate)
+	b.last = now
+	if b.tokens >= 1 {
+		b.tokens--
+		return 0
+	}
+	return time.Duration((1 - b.tokens) / g.rate * float64(time.Second))
+}
+
+// sweep forgets the clients whose bucket is full again.
+func (g *guard) sweep() {
+	if g.rate <= 0 {
+		return
+	}
+
+	g.mutex.Lock()
+	defer g.mutex.Unlock()
+
+	idle := time.Duration(g.burst / g.rate * float64(time.Second))
+	for k, v := range g.buckets {
+		if time.Since(v.last) > idle {
+			delete(g.buckets, k)
+		}
+	}
+}
+
+// wrap returns the handler behind the guard.
+func (g *guard) wrap(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if g.token != "" {
+			given, _ := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if subtle.ConstantTimeCompare([]byte(given), []byte(g.token)) != 1 {
+				reply(w, http.StatusUnauthorized, map[string]string{"error": "invalid token"})
+				return
+			}
+		}
+
+		if d := g.allow(client(r)); d > 0 {
+			w.Header().Set("Retry-After", strconv.Itoa(int(d.Seconds())+1))
+			reply(w, http.StatusTooManyRequests, map[string]string{"error": "too many requests"})
+			return
+		}
+
+		select {
+		case g.slots <- struct{}{}:
+			defer func() { <-g.slots }()
+		case <-r.Context().Done():
+			return
+		}
+		next(w, r)
+	}
+}
+
+// client returns the address the request came from, without its port.
+func client(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
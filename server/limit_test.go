@@ -0,0 +1,99 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func ok(w http.ResponseWriter, _ *http.Request) {
+	w.WriteHeader(http.StatusOK)
+}
+
+func serve(h http.HandlerFunc, remote, token string) *httptest.ResponseRecorder {
+	r := httptest.NewRequest(http.MethodGet, "/anime/1/ids", nil)
+	r.RemoteAddr = remote
+	if token != "" {
+		r.Header.Set("Authorization", "Bearer "+token)
+	}
+	w := httptest.NewRecorder()
+	h(w, r)
+	return w
+}
+
+func TestGuardToken(t *testing.T) {
+	h := newGuard("secret", 0, 1, 1).wrap(ok)
+
+	assert.Equal(t, http.StatusUnauthorized, serve(h, "10.0.0.1:1000", "").Code)
+	assert.Equal(t, http.StatusUnauthorized, serve(h, "10.0.0.1:1000", "wrong").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "10.0.0.1:1000", "secret").Code)
+
+	// without a token every client is let in
+	h = newGuard("", 0, 1, 1).wrap(ok)
+	assert.Equal(t, http.StatusOK, serve(h, "10.0.0.1:1000", "").Code)
+}
+
+func TestGuardRate(t *testing.T) {
+	h := newGuard("", 60, 2, 4).wrap(ok)
+
+	assert.Equal(t, http.StatusOK, serve(h, "10.0.0.1:1000", "").Code)
+	assert.Equal(t, http.StatusOK, serve(h, "10.0.0.1:2000", "").Code, "the port is not part of the client")
+
+	w := serve(h, "10.0.0.1:3000", "")
+	assert.Equal(t, http.StatusTooManyRequests, w.Code)
+	after, err := strconv.Atoi(w.Header().Get("Retry-After"))
+	require.NoError(t, err)
+	assert.InDelta(t, 1, after, 1)
+
+	// every client has its own bucket
+	assert.Equal(t, http.StatusOK, serve(h, "10.0.0.2:1000", "").Code)
+}
+
+func TestGuardNoRate(t *testing.T) {
+	h := newGuard("", 0, 1, 4).wrap(ok)
+	for range 50 {
+		assert.Equal(t, http.StatusOK, serve(h, "10.0.0.1:1000", "").Code)
+	}
+}
+
+func TestGuardSweep(t *testing.T) {
+	g := newGuard("", 6000, 1, 1)
+	g.allow("10.0.0.1")
+	g.allow("10.0.0.2")
+	g.buckets["10.0.0.1"].last = time.Now().Add(-time.Minute)
+
+	g.sweep()
+	assert.NotContains(t, g.buckets, "10.0.0.1")
+	assert.Contains(t, g.buckets, "10.0.0.2")
+}
+
+func TestGuardConcurrency(t *testing.T) {
+	var (
+		started = make(chan struct{})
+		release = make(chan struct{})
+	)
+	h := newGuard("", 0, 1, 1).wrap(func(w http.ResponseWriter, r *http.Request) {
+		started <- struct{}{}
+		<-release
+	})
+
+	go serve(h, "10.0.0.1:1000", "")
+	<-started
+
+	// the only slot is taken, a request that gives up before it frees writes nothing
+	r := httptest.NewRequest(http.MethodGet, "/anime/1/ids", nil)
+	ctx, cancel := context.WithTimeout(r.Context(), 20*time.Millisecond)
+	defer cancel()
+	w := httptest.NewRecorder()
+	h(w, r.WithContext(ctx))
+	assert.False(t, w.Flushed)
+	assert.Empty(t, w.Body.String())
+
+	close(release)
+}
@@ -0,0 +1,166 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+var logger = slog.Default().WithGroup("[SERVER]")
+
+// Limits protect the endpoints that reach the sites.
+type Limits struct {
+	// Token, when set, must be sent as "Authorization: Bearer <token>".
+	Token string
+	// Rate is the number of requests per minute allowed to each client, zero disables it.
+	Rate float64
+	// Burst is the number of requests a client can send at once.
+	Burst int
+	// Concurrency caps the requests running at once across every client.
+	Concurrency int
+}
+
+// Server exposes the scrape, mapping and resource packages over HTTP/JSON.
+type Server struct {
+	http     *http.Server
+	guard    *guard
+	certFile string
+	keyFile  string
+}
+
+// New creates a server listening on the address, it serves HTTPS when both the
+// certificate and the key files are set.
+func New(addr, certFile, keyFile string, limits Limits) *Server {
+	x := &Server{
+		guard:    newGuard(limits.Token, limits.Rate, limits.Burst, limits.Concurrency),
+		certFile: certFile,
+		keyFile:  keyFile,
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /anime/{mal}/episodes/{n}/embeds", x.guard.wrap(embeds))
+	mux.HandleFunc("GET /anime/{mal}/ids", x.guard.wrap(ids))
+	mux.HandleFunc("GET /anime/{mal}/themes", x.guard.wrap(themes))
+	mux.HandleFunc("GET /anime/{mal}/art", x.guard.wrap(art))
+	mux.HandleFunc("GET /providers", providers)
+
+	x.http = &http.Server{
+		Addr:              addr,
+		Handler:           recovery(mux),
+		ReadHeaderTimeout: 10 * time.Second,
+		IdleTimeout:       2 * time.Minute,
+	}
+	return x
+}
+
+// Run serves until the context is done, then shuts down gracefully.
+func (x *Server) Run(ctx context.Context) error {
+	fail := make(chan error, 1)
+	go func() {
+		var err error
+		if x.certFile != "" && x.keyFile != "" {
+			logger.Info("serving https", "addr", x.http.Addr)
+			err = x.http.ListenAndServeTLS(x.certFile, x.keyFile)
+		} else {
+			logger.Info("serving http", "addr", x.http.Addr)
+			err = x.http.ListenAndServe()
+		}
+		fail <- err
+	}()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+loop:
+	for {
+		select {
+		case err := <-fail:
+			return err
+		case <-ticker.C:
+			x.guard.sweep()
+		case <-ctx.Done():
+			break loop
+		}
+	}
+
+	shutdown, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	return x.http.Shutdown(shutdown)
+}
+
+// recovery turns a panic of a handler into a 500 response, or drops the connection when the
+// handler had already started its response.
+func recovery(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &tracker{ResponseWriter: w}
+		defer func() {
+			v := recover()
+			if v == nil {
+				return
+			}
+			if v == http.ErrAbortHandler {
+				panic(v)
+			}
+			logger.Error("handler panicked", "path", r.URL.Path, "panic", v)
+			if rw.wrote {
+				panic(http.ErrAbortHandler)
+			}
+			reply(w, http.StatusInternalServerError, map[string]string{"error": "internal error"})
+		}()
+		start := time.Now()
+		next.ServeHTTP(rw, r)
+		logger.Info("request served", "method", r.Method, "path", r.URL.Path, "took", time.Since(start))
+	})
+}
+
+// tracker records whether the handler started its response.
+type tracker struct {
+	http.ResponseWriter
+	wrote bool
+}
+
+func (x *tracker) WriteHeader(code int) {
+	x.wrote = true
+	x.ResponseWriter.WriteHeader(code)
+}
+
+func (x *tracker) Write(p []byte) (int, error) {
+	x.wrote = true
+	return x.ResponseWriter.Write(p)
+}
+
+// Unwrap lets http.ResponseController reach the writer of the server.
+func (x *tracker) Unwrap() http.ResponseWriter {
+	return x.ResponseWriter
+}
+
+func reply(w http.ResponseWriter, status int, data any) {
+	w.Header().Set("Content-Type", "application/json; charset=utf-8")
+	w.WriteHeader(status)
+
+	enc := json.NewEncoder(w)
+	enc.SetEscapeHTML(false)
+	if err := enc.Encode(data); err != nil {
+		logger.Error("cannot write response", "error", err)
+	}
+}
+
+// fail maps the error to a status code and writes it.
+func fail(w http.ResponseWriter, err error) {
+	status := http.StatusBadGateway
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		status = http.StatusServiceUnavailable
+	case errors.Is(err, errs.ErrNotFound):
+		status = http.StatusNotFound
+	case errors.Is(err, errs.ErrBadData):
+		status = http.StatusBadRequest
+	}
+
+	reply(w, status, map[string]string{"error": err.Error()})
+}
@@ -0,0 +1,91 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFail(t *testing.T) {
+	for _, v := range []struct {
+		err    error
+		status int
+	}{
+		{errs.ErrNotFound, http.StatusNotFound},
+		{fmt.Errorf("%w: invalid mal id", errs.ErrBadData), http.StatusBadRequest},
+		{&errs.Error{Provider: "gogoanime", Err: errs.ErrNotFound}, http.StatusNotFound},
+		{context.Canceled, http.StatusServiceUnavailable},
+		{fmt.Errorf("scrape: %w", context.DeadlineExceeded), http.StatusServiceUnavailable},
+		{errs.ErrNoData, http.StatusBadGateway},
+		{errors.New("connection reset"), http.StatusBadGateway},
+	} {
+		w := httptest.NewRecorder()
+		fail(w, v.err)
+
+		assert.Equal(t, v.status, w.Code, v.err.Error())
+		assert.Equal(t, "application/json; charset=utf-8", w.Header().Get("Content-Type"))
+		var body map[string]string
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+		assert.Equal(t, v.err.Error(), body["error"])
+	}
+}
+
+func TestRecovery(t *testing.T) {
+	h := recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}))
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	assert.JSONEq(t, `{"error":"internal error"}`, w.Body.String())
+
+	// a response already started is not written a second time, the connection is dropped
+	h = recovery(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		reply(w, http.StatusOK, map[string]string{"status": "partial"})
+		panic("boom")
+	}))
+	w = httptest.NewRecorder()
+	assert.PanicsWithValue(t, http.ErrAbortHandler, func() {
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+	})
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"status":"partial"}`, w.Body.String())
+}
+
+func TestRoutes(t *testing.T) {
+	h := New(":0", "", "", Limits{Token: "secret", Concurrency: 1}).http.Handler
+
+	do := func(method, path, token string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest(method, path, nil)
+		if token != "" {
+			r.Header.Set("Authorization", "Bearer "+token)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w
+	}
+
+	w := do(http.MethodGet, "/providers", "")
+	assert.Equal(t, http.StatusOK, w.Code, "the provider list needs no token")
+	var list []struct {
+		Name string `json:"Name"`
+		Host string `json:"Host"`
+	}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &list))
+	assert.NotEmpty(t, list)
+
+	assert.Equal(t, http.StatusUnauthorized, do(http.MethodGet, "/anime/52991/ids", "").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/anime/abc/ids?title=Frieren", "secret").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/anime/52991/ids?title=Frieren&start=sometime", "secret").Code)
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/anime/52991/episodes/x/embeds?title=Frieren", "secret").Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/anime/52991", "secret").Code)
+	assert.Equal(t, http.StatusMethodNotAllowed, do(http.MethodPost, "/providers", "").Code)
+}
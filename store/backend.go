@@ -0,0 +1,21 @@
+package store
+
+import "time"
+
+// Kinds of documents kept by the store.
+const (
+	KindAnime    = "anime"
+	KindFinal    = "final"
+	KindResource = "resource"
+	KindEmbeds   = "embeds"
+)
+
+// Backend keeps raw JSON documents by kind and key together with the time they were written.
+// Get returns errs.ErrNotFound for a missing document.
+type Backend interface {
+	Get(kind, key string) ([]byte, time.Time, error)
+	Put(kind, key string, data []byte) error
+	Delete(kind, key string) error
+	Keys(kind string) ([]string, error)
+	Close() error
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// TestBackends runs the same checks against every backend, reopening it to make sure the
+// documents outlive the process.
+func TestBackends(t *testing.T) {
+	for name, open := range map[string]func(dir string) (Backend, error){
+		"dir": func(dir string) (Backend, error) {
+			return NewDir(filepath.Join(dir, "store"))
+		},
+		"bolt": func(dir string) (Backend, error) {
+			return NewBolt(filepath.Join(dir, "nested", "store.db"))
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dir := t.TempDir()
+			backend, err := open(dir)
+			require.NoError(t, err)
+
+			_, _, err = backend.Get(KindAnime, "1")
+			assert.ErrorIs(t, err, errs.ErrNotFound)
+			keys, err := backend.Keys(KindAnime)
+			require.NoError(t, err)
+			assert.Empty(t, keys)
+
+			before := time.Now().UTC().Add(-time.Second)
+			require.NoError(t, backend.Put(KindAnime, "1", []byte(`{"Mal":1}`)))
+			require.NoError(t, backend.Put(KindAnime, "2", []byte(`{"Mal":2}`)))
+			require.NoError(t, backend.Put(KindFinal, "1", []byte(`{"Mal":1}`)))
+			require.NoError(t, backend.Put(KindAnime, "1", []byte(`{"Mal":1,"Title":"Frieren"}`)))
+
+			data, updated, err := backend.Get(KindAnime, "1")
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"Mal":1,"Title":"Frieren"}`, string(data))
+			assert.True(t, updated.After(before))
+
+			keys, err = backend.Keys(KindAnime)
+			require.NoError(t, err)
+			assert.ElementsMatch(t, []string{"1", "2"}, keys)
+
+			require.NoError(t, backend.Delete(KindAnime, "2"))
+			require.NoError(t, backend.Delete(KindAnime, "2"), "a missing document is not an error")
+			require.NoError(t, backend.Delete(KindEmbeds, "1"), "a missing kind is not an error")
+			_, _, err = backend.Get(KindAnime, "2")
+			assert.ErrorIs(t, err, errs.ErrNotFound)
+
+			require.NoError(t, backend.Close())
+			backend, err = open(dir)
+			require.NoError(t, err)
+			defer backend.Close()
+
+			data, _, err = backend.Get(KindAnime, "1")
+			require.NoError(t, err)
+			assert.JSONEq(t, `{"Mal":1,"Title":"Frieren"}`, string(data))
+			keys, err = backend.Keys(KindFinal)
+			require.NoError(t, err)
+			assert.Equal(t, []string{"1"}, keys)
+		})
+	}
+}
+
+func TestOpen(t *testing.T) {
+	dir := t.TempDir()
+
+	s, err := Open(filepath.Join(dir, "store.db"))
+	require.NoError(t, err)
+	assert.IsType(t, &Bolt{}, s.backend)
+	require.NoError(t, s.Close())
+
+	s, err = Open(filepath.Join(dir, "store"))
+	require.NoError(t, err)
+	assert.IsType(t, &Dir{}, s.backend)
+	require.NoError(t, s.Close())
+}
+
+func TestList(t *testing.T) {
+	backend, err := NewDir(t.TempDir())
+	require.NoError(t, err)
+	s := New(backend)
+
+	for _, v := range []string{"30", "5", "not-an-id", "120"} {
+		require.NoError(t, backend.Put(KindResource, v, []byte(`{}`)))
+	}
+
+	ids, err := s.List(KindResource)
+	require.NoError(t, err)
+	assert.Equal(t, []int{5, 30, 120}, ids)
+
+	assert.ErrorIs(t, s.put(KindResource, 0, struct{}{}), errs.ErrBadData)
+}
@@ -0,0 +1,99 @@
+package store
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	bolt "go.etcd.io/bbolt"
+)
+
+// Bolt is an embedded backend keeping every document in a single bbolt file, one bucket per
+// kind. Only the documents being read are loaded in memory and a write replaces its document
+// in place, so the file does not grow with every save.
+type Bolt struct {
+	db *bolt.DB
+}
+
+// NewBolt opens or creates the database file.
+func NewBolt(path string) (*Bolt, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, err
+	}
+
+	// another process holding the file makes the open fail instead of waiting forever.
+	db, err := bolt.Open(path, 0o644, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, err
+	}
+	return &Bolt{db: db}, nil
+}
+
+func (b *Bolt) Get(kind, key string) ([]byte, time.Time, error) {
+	var doc document
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(kind))
+		if bucket == nil {
+			return errs.ErrNotFound
+		}
+		body := bucket.Get([]byte(key))
+		if body == nil {
+			return errs.ErrNotFound
+		}
+		// the value is only valid during the transaction, Unmarshal copies it.
+		return json.Unmarshal(body, &doc)
+	})
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return doc.Data, doc.Updated, nil
+}
+
+func (b *Bolt) Put(kind, key string, data []byte) error {
+	if data == nil {
+		data = json.RawMessage("null")
+	}
+	body, err := json.Marshal(document{Updated: time.Now().UTC(), Data: data})
+	if err != nil {
+		return err
+	}
+
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket, err := tx.CreateBucketIfNotExists([]byte(kind))
+		if err != nil {
+			return err
+		}
+		return bucket.Put([]byte(key), body)
+	})
+}
+
+func (b *Bolt) Delete(kind, key string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(kind))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.Delete([]byte(key))
+	})
+}
+
+func (b *Bolt) Keys(kind string) ([]string, error) {
+	var keys []string
+	err := b.db.View(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket([]byte(kind))
+		if bucket == nil {
+			return nil
+		}
+		return bucket.ForEach(func(k, _ []byte) error {
+			keys = append(keys, string(k))
+			return nil
+		})
+	})
+	return keys, err
+}
+
+func (b *Bolt) Close() error {
+	return b.db.Close()
+}
@@ -0,0 +1,100 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+)
+
+// document is the on disk form of a value.
+type document struct {
+	Updated time.Time       `json:"Updated"`
+	Data    json.RawMessage `json:"Data"`
+}
+
+// Dir is a backend storing every document as an indented JSON file at <dir>/<kind>/<key>.json,
+// easy to read and to diff by hand.
+type Dir struct {
+	dir string
+}
+
+// NewDir creates the directory backend.
+func NewDir(dir string) (*Dir, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return &Dir{dir: dir}, nil
+}
+
+func (d *Dir) path(kind, key string) string {
+	return filepath.Join(d.dir, kind, key+".json")
+}
+
+func (d *Dir) Get(kind, key string) ([]byte, time.Time, error) {
+	body, err := os.ReadFile(d.path(kind, key))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, time.Time{}, errs.ErrNotFound
+		}
+		return nil, time.Time{}, err
+	}
+
+	var doc document
+	if err = json.Unmarshal(body, &doc); err != nil {
+		return nil, time.Time{}, err
+	}
+	return doc.Data, doc.Updated, nil
+}
+
+func (d *Dir) Put(kind, key string, data []byte) error {
+	body, err := json.MarshalIndent(document{Updated: time.Now().UTC(), Data: data}, "", "  ")
+	if err != nil {
+		return err
+	}
+
+	path := d.path(kind, key)
+	if err = os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+	tmp := path + ".tmp"
+	if err = os.WriteFile(tmp, body, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+func (d *Dir) Delete(kind, key string) error {
+	err := os.Remove(d.path(kind, key))
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+func (d *Dir) Keys(kind string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(d.dir, kind))
+	if err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var keys []string
+	for _, v := range entries {
+		if name, ok := strings.CutSuffix(v.Name(), ".json"); ok && !v.IsDir() {
+			keys = append(keys, name)
+		}
+	}
+	return keys, nil
+}
+
+func (d *Dir) Close() error {
+	return nil
+}
@@ -0,0 +1,143 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/verify"
+)
+
+// KindHosts holds the link check history of every embed host in a single document.
+const KindHosts = "hosts"
+
+// HostRecord counts the checks of the links of an embed host.
+type HostRecord struct {
+	Alive   int       `json:"Alive"`
+	Dead    int       `json:"Dead"`
+	Updated time.Time `json:"Updated"`
+}
+
+// Hosts returns the check history of every embed host by name, see analyze.ExtractSourceHost.
+func (s *Store) Hosts() (map[string]*HostRecord, error) {
+	hosts := make(map[string]*HostRecord)
+
+	data, _, err := s.backend.Get(KindHosts, "all")
+	if err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return hosts, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &hosts); err != nil {
+		return nil, err
+	}
+	return hosts, nil
+}
+
+// SaveChecks adds the checked links to the history of their hosts. Links that are geo blocked
+// or behind a challenge count as dead, links in an unknown state are not counted.
+func (s *Store) SaveChecks(links []*verify.Link) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	hosts, err := s.Hosts()
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, v := range links {
+		if v.Status == verify.Unknown {
+			continue
+		}
+		name := analyze.ExtractSourceHost(v.Video.Source)
+		if name == "" {
+			continue
+		}
+
+		record, ok := hosts[name]
+		if !ok {
+			record = new(HostRecord)
+			hosts[name] = record
+		}
+		if v.Status == verify.Alive {
+			record.Alive++
+		} else {
+			record.Dead++
+		}
+		record.Updated = now
+	}
+
+	data, err := json.Marshal(hosts)
+	if err != nil {
+		return err
+	}
+	return s.backend.Put(KindHosts, "all", data)
+}
+
+// SaveStates removes the links found dead from the stored embeds of the anime, matched by
+// provider, episode and source. An embed left without any link is scraped again by Plan.
+func (s *Store) SaveStates(mal int, links []*verify.Link) error {
+	type key struct {
+		provider string
+		number   int
+		download bool
+		source   string
+	}
+	dead := make(map[key]bool)
+	for _, v := range links {
+		if v.Status == verify.Dead && v.Provider != "" {
+			dead[key{v.Provider, v.Episode, v.Download, v.Video.Source}] = true
+		}
+	}
+	if len(dead) == 0 {
+		return nil
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.Embeds(mal)
+	if err != nil {
+		return err
+	}
+
+	var found bool
+	for _, e := range data {
+		prune := func(download bool) func(models.AnimeVideo) bool {
+			return func(v models.AnimeVideo) bool {
+				if dead[key{e.Provider, e.Number, download, v.Source}] {
+					found = true
+					return true
+				}
+				return false
+			}
+		}
+		e.Videos = slices.DeleteFunc(e.Videos, prune(false))
+		e.Download = slices.DeleteFunc(e.Download, prune(true))
+	}
+	if !found {
+		return nil
+	}
+	return s.put(KindEmbeds, mal, data)
+}
+
+// Reliability returns the share of alive links of every host, smoothed so a host with few
+// checks stays close to one half.
+func (s *Store) Reliability() (map[string]float64, error) {
+	hosts, err := s.Hosts()
+	if err != nil {
+		return nil, err
+	}
+
+	data := make(map[string]float64, len(hosts))
+	for k, v := range hosts {
+		data[k] = float64(v.Alive+1) / float64(v.Alive+v.Dead+2)
+	}
+	return data, nil
+}
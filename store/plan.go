@@ -0,0 +1,119 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"slices"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+// ErrNoEpisodes is returned by Plan when no episodes were given and the episode list of the
+// anime was never stored, so there is nothing to compare against.
+var ErrNoEpisodes = fmt.Errorf("%w: no episode list is stored for the anime", errs.ErrNotFound)
+
+// Aired returns the numbers of the regular episodes of the stored anime that are out,
+// either flagged as aired or with a release time in the past. It returns errs.ErrNotFound
+// when the anime or its episode list was never stored.
+func (s *Store) Aired(mal int) ([]int, error) {
+	anime, _, err := s.Anime(mal)
+	if err != nil {
+		return nil, err
+	}
+	if len(anime.Episodes) == 0 {
+		return nil, errs.ErrNotFound
+	}
+
+	var (
+		now   = time.Now().Unix()
+		aired []int
+	)
+	for _, v := range anime.Episodes {
+		n := int(v.Number)
+		if v.Special || n <= 0 || float32(n) != v.Number {
+			continue
+		}
+		if v.Aired || (v.ReleaseTime.Unix > 0 && v.ReleaseTime.Unix <= now) {
+			aired = append(aired, n)
+		}
+	}
+	slices.Sort(aired)
+	return slices.Compact(aired), nil
+}
+
+// Plan returns, for every provider, the episodes that have to be scraped: the ones it has no
+// stored embed for, the ones whose links were all found dead, see SaveStates, and the ones scraped
+// longer than maxAge ago, a zero maxAge never expires them. When the episode list of the anime is
+// stored, episodes that have not aired are left out and an empty episodes list means every aired
+// episode, without that list it returns ErrNoEpisodes. Providers with nothing to do are not in
+// the plan.
+func (s *Store) Plan(mal int, episodes []int, providers []string, maxAge time.Duration) (map[string][]int, error) {
+	aired, err := s.Aired(mal)
+	if err != nil && !errors.Is(err, errs.ErrNotFound) {
+		return nil, err
+	}
+	known := err == nil
+	switch {
+	case len(episodes) == 0:
+		if !known {
+			return nil, ErrNoEpisodes
+		}
+		episodes = aired
+	case known:
+		episodes = slices.DeleteFunc(slices.Clone(episodes), func(n int) bool {
+			_, ok := slices.BinarySearch(aired, n)
+			return !ok
+		})
+	}
+
+	embeds, err := s.Embeds(mal)
+	if err != nil {
+		return nil, err
+	}
+
+	type key struct {
+		provider string
+		number   int
+	}
+	updated := make(map[key]time.Time, len(embeds))
+	for _, v := range embeds {
+		// every link of the episode was pruned, it is as good as missing
+		if len(v.Videos) == 0 && len(v.Download) == 0 {
+			continue
+		}
+		updated[key{v.Provider, v.Number}] = v.Updated
+	}
+
+	plan := make(map[string][]int)
+	for _, p := range providers {
+		for _, n := range episodes {
+			t, ok := updated[key{p, n}]
+			if ok && (maxAge <= 0 || time.Since(t) < maxAge) {
+				continue
+			}
+			plan[p] = append(plan[p], n)
+		}
+	}
+	return plan, nil
+}
+
+// Restrict sets opts.Episodes to the plan of the anime for the providers RunAll would use,
+// it reports false when no provider has anything left to scrape.
+func (s *Store) Restrict(info *models.AnimeInfo, episodes []int, opts *scrape.Options, maxAge time.Duration) (bool, error) {
+	providers := opts.Providers
+	if len(providers) == 0 {
+		for _, v := range scrape.Scrapers(info.Type) {
+			providers = append(providers, v.Name())
+		}
+	}
+
+	plan, err := s.Plan(info.MalID, episodes, providers, maxAge)
+	if err != nil {
+		return false, err
+	}
+	opts.Episodes = plan
+	return len(plan) > 0, nil
+}
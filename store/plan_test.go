@@ -0,0 +1,103 @@
+package store
+
+import (
+	"testing"
+	"time"
+
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+	"github.com/anicine/anicine-scraper/verify"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newStore(t *testing.T) *Store {
+	backend, err := NewDir(t.TempDir())
+	require.NoError(t, err)
+	return New(backend)
+}
+
+func embed(number int, sources ...string) *scrape.EmbedNode {
+	node := &scrape.EmbedNode{Number: number}
+	for _, v := range sources {
+		node.Videos = append(node.Videos, models.AnimeVideo{Source: v})
+	}
+	return node
+}
+
+func TestPlanWithoutEpisodeList(t *testing.T) {
+	s := newStore(t)
+
+	_, err := s.Plan(1, nil, []string{"gogoanime"}, 0)
+	assert.ErrorIs(t, err, ErrNoEpisodes)
+
+	// given episodes are planned without the list
+	plan, err := s.Plan(1, []int{1, 2}, []string{"gogoanime"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"gogoanime": {1, 2}}, plan)
+}
+
+func TestPlanAired(t *testing.T) {
+	s := newStore(t)
+	require.NoError(t, s.SaveAnime(1, &models.Anime{
+		Episodes: []models.AnimeEpisode{
+			{Number: 1, Aired: true},
+			{Number: 2, ReleaseTime: models.AnimeTime{Unix: time.Now().Add(-time.Hour).Unix()}},
+			{Number: 3, ReleaseTime: models.AnimeTime{Unix: time.Now().Add(time.Hour).Unix()}},
+			{Number: 4, Aired: true, Special: true},
+			{Number: 4.5, Aired: true},
+		},
+	}))
+	require.NoError(t, s.SaveEmbeds(1, "gogoanime", []*scrape.EmbedNode{embed(1, "https://a/1")}))
+
+	plan, err := s.Plan(1, nil, []string{"gogoanime", "witanime"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"gogoanime": {2}, "witanime": {1, 2}}, plan)
+
+	// an episode that has not aired is left out even when asked for
+	plan, err = s.Plan(1, []int{2, 3}, []string{"gogoanime"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"gogoanime": {2}}, plan)
+
+	// a stored list without any aired episode still filters
+	require.NoError(t, s.SaveAnime(2, &models.Anime{
+		Episodes: []models.AnimeEpisode{{Number: 1}},
+	}))
+	plan, err = s.Plan(2, []int{1}, []string{"gogoanime"}, 0)
+	require.NoError(t, err)
+	assert.Empty(t, plan)
+}
+
+func TestPlanStale(t *testing.T) {
+	s := newStore(t)
+	require.NoError(t, s.SaveEmbeds(1, "gogoanime", []*scrape.EmbedNode{
+		embed(1, "https://a/1"),
+		embed(2, "https://a/2", "https://b/2"),
+	}))
+
+	plan, err := s.Plan(1, []int{1, 2}, []string{"gogoanime"}, time.Hour)
+	require.NoError(t, err)
+	assert.Empty(t, plan, "fresh embeds are up to date")
+
+	plan, err = s.Plan(1, []int{1, 2}, []string{"gogoanime"}, time.Nanosecond)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"gogoanime": {1, 2}}, plan, "embeds older than the max age")
+
+	// every link of episode 1 and one of episode 2 are dead
+	require.NoError(t, s.SaveStates(1, []*verify.Link{
+		{Provider: "gogoanime", Episode: 1, Video: models.AnimeVideo{Source: "https://a/1"}, Status: verify.Dead},
+		{Provider: "gogoanime", Episode: 2, Video: models.AnimeVideo{Source: "https://a/2"}, Status: verify.Dead},
+		{Provider: "gogoanime", Episode: 2, Video: models.AnimeVideo{Source: "https://b/2"}, Status: verify.Alive},
+		{Provider: "witanime", Episode: 2, Video: models.AnimeVideo{Source: "https://b/2"}, Status: verify.Dead},
+	}))
+
+	plan, err = s.Plan(1, []int{1, 2}, []string{"gogoanime"}, 0)
+	require.NoError(t, err)
+	assert.Equal(t, map[string][]int{"gogoanime": {1}}, plan)
+
+	embeds, err := s.Embeds(1)
+	require.NoError(t, err)
+	require.Len(t, embeds, 2)
+	assert.Empty(t, embeds[0].Videos)
+	assert.Equal(t, []models.AnimeVideo{{Source: "https://b/2"}}, embeds[1].Videos)
+}
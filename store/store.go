@@ -0,0 +1,226 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anicine/anicine-scraper/internal/errs"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+// Embed is an episode found by a provider, Updated is when it was last scraped.
+type Embed struct {
+	scrape.EmbedNode
+	Provider string    `json:"Provider"`
+	Updated  time.Time `json:"Updated"`
+}
+
+// Store saves the anime, the mappings and the embeds by MyAnimeList id on a backend,
+// so later runs can compare against what was already scraped.
+type Store struct {
+	backend Backend
+	mutex   sync.Mutex
+}
+
+// New creates a store on the backend.
+func New(backend Backend) *Store {
+	return &Store{backend: backend}
+}
+
+// Open opens a store on a path, a path ending with .db is the embedded bbolt backend and
+// anything else is a directory of JSON files.
+func Open(path string) (*Store, error) {
+	var (
+		backend Backend
+		err     error
+	)
+	if strings.HasSuffix(path, ".db") {
+		backend, err = NewBolt(path)
+	} else {
+		backend, err = NewDir(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return New(backend), nil
+}
+
+// Close closes the backend.
+func (s *Store) Close() error {
+	return s.backend.Close()
+}
+
+func (s *Store) get(kind string, mal int, value any) (time.Time, error) {
+	data, updated, err := s.backend.Get(kind, strconv.Itoa(mal))
+	if err != nil {
+		return time.Time{}, err
+	}
+	return updated, json.Unmarshal(data, value)
+}
+
+func (s *Store) put(kind string, mal int, value any) error {
+	if mal <= 0 {
+		return errs.ErrBadData
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return s.backend.Put(kind, strconv.Itoa(mal), data)
+}
+
+// Anime returns the stored anime and when it was saved.
+func (s *Store) Anime(mal int) (*models.Anime, time.Time, error) {
+	data := new(models.Anime)
+	updated, err := s.get(KindAnime, mal, data)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, updated, nil
+}
+
+// SaveAnime replaces the stored anime.
+func (s *Store) SaveAnime(mal int, data *models.Anime) error {
+	return s.put(KindAnime, mal, data)
+}
+
+// Final returns the stored final anime and when it was saved.
+func (s *Store) Final(mal int) (*models.FinalAnime, time.Time, error) {
+	data := new(models.FinalAnime)
+	updated, err := s.get(KindFinal, mal, data)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, updated, nil
+}
+
+// SaveFinal replaces the stored final anime.
+func (s *Store) SaveFinal(mal int, data *models.FinalAnime) error {
+	return s.put(KindFinal, mal, data)
+}
+
+// Resource returns the stored mapping ids and when they were saved.
+func (s *Store) Resource(mal int) (*models.AnimeResource, time.Time, error) {
+	data := new(models.AnimeResource)
+	updated, err := s.get(KindResource, mal, data)
+	if err != nil {
+		return nil, time.Time{}, err
+	}
+	return data, updated, nil
+}
+
+// SaveResource replaces the stored mapping ids.
+func (s *Store) SaveResource(mal int, data *models.AnimeResource) error {
+	return s.put(KindResource, mal, data)
+}
+
+// Embeds returns every stored embed of the anime sorted by episode and provider,
+// an anime that was never scraped has no embeds and no error.
+func (s *Store) Embeds(mal int) ([]*Embed, error) {
+	var data []*Embed
+	if _, err := s.get(KindEmbeds, mal, &data); err != nil {
+		if errors.Is(err, errs.ErrNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return data, nil
+}
+
+// SaveEmbeds replaces the episodes the provider returned and keeps every other one,
+// the saved episodes are stamped with the current time.
+func (s *Store) SaveEmbeds(mal int, provider string, nodes []*scrape.EmbedNode) error {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	data, err := s.Embeds(mal)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now().UTC()
+	for _, v := range nodes {
+		if v == nil {
+			continue
+		}
+		data = slices.DeleteFunc(data, func(e *Embed) bool {
+			return e.Provider == provider && e.Number == v.Number
+		})
+		data = append(data, &Embed{EmbedNode: *v, Provider: provider, Updated: now})
+	}
+	slices.SortFunc(data, func(a, b *Embed) int {
+		if a.Number != b.Number {
+			return a.Number - b.Number
+		}
+		if a.Provider < b.Provider {
+			return -1
+		}
+		if a.Provider > b.Provider {
+			return 1
+		}
+		return 0
+	})
+
+	return s.put(KindEmbeds, mal, data)
+}
+
+// SaveResult saves the embeds of every provider of a scrape.RunAll result, it must be called
+// before Result.Merge which keeps a shared video under a single provider.
+func (s *Store) SaveResult(mal int, result *scrape.Result) error {
+	providers := make(map[string]map[int]*scrape.EmbedNode)
+	for _, ep := range result.Episodes {
+		for i, list := range [][]scrape.SourceVideo{ep.Videos, ep.Download} {
+			for _, v := range list {
+				nodes, ok := providers[v.Provider]
+				if !ok {
+					nodes = make(map[int]*scrape.EmbedNode)
+					providers[v.Provider] = nodes
+				}
+				node, ok := nodes[ep.Number]
+				if !ok {
+					node = &scrape.EmbedNode{Number: ep.Number}
+					nodes[ep.Number] = node
+				}
+				if i == 0 {
+					node.Videos = append(node.Videos, v.Video)
+				} else {
+					node.Download = append(node.Download, v.Video)
+				}
+			}
+		}
+	}
+
+	for name, nodes := range providers {
+		var list []*scrape.EmbedNode
+		for _, v := range nodes {
+			list = append(list, v)
+		}
+		if err := s.SaveEmbeds(mal, name, list); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// List returns the MyAnimeList ids stored under the kind.
+func (s *Store) List(kind string) ([]int, error) {
+	keys, err := s.backend.Keys(kind)
+	if err != nil {
+		return nil, err
+	}
+
+	var ids []int
+	for _, v := range keys {
+		if id, err := strconv.Atoi(v); err == nil {
+			ids = append(ids, id)
+		}
+	}
+	slices.Sort(ids)
+	return ids, nil
+}
@@ -0,0 +1,318 @@
+package verify
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"log/slog"
+	"net"
+	"net/http"
+	"net/url"
+	"path"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/anicine/anicine-scraper/internal/analyze"
+	"github.com/anicine/anicine-scraper/models"
+	"github.com/anicine/anicine-scraper/scrape"
+)
+
+var logger = slog.Default().WithGroup("[VERIFY]")
+
+// Status is the state of a link.
+type Status string
+
+const (
+	Alive      Status = "alive"
+	Dead       Status = "dead"
+	Geo        Status = "geo"
+	Cloudflare Status = "cloudflare"
+	// Unknown is a link that timed out or failed in a way that may not last, it is never pruned.
+	Unknown Status = "unknown"
+)
+
+// the pages of the embed hosts often answer 200 with an error message in the player.
+var (
+	deadMarks = []string{
+		"file not found",
+		"file was deleted",
+		"file has been deleted",
+		"video not found",
+		"video has been removed",
+		"video was deleted",
+		"this video doesn't exist",
+		"video is not available",
+		"file is no longer available",
+	}
+	geoMarks = []string{
+		"not available in your country",
+		"not available in your region",
+		"geo restricted",
+		"geo-restricted",
+		"geoblocked",
+	}
+	// only the titles of the interstitials, the challenge scripts are also injected into
+	// the normal pages of the hosts behind Cloudflare.
+	challengeMarks = []string{
+		"<title>just a moment...</title>",
+		"<title>attention required! | cloudflare</title>",
+	}
+)
+
+const (
+	// the part of a page read to look for the marks.
+	pageLimit = 256 << 10
+	// the time a single link has to answer, a host that hangs does not hold the others.
+	linkTimeout = 30 * time.Second
+)
+
+// the extensions of the links that are media files rather than player pages.
+var mediaExts = []string{".mp4", ".m4v", ".mkv", ".webm", ".avi", ".mov", ".flv", ".ts", ".m3u8", ".mpd"}
+
+// Link is a video together with the state of its source.
+type Link struct {
+	Provider string            `json:"Provider,omitempty"`
+	Episode  int               `json:"Episode,omitempty"`
+	Download bool              `json:"Download,omitempty"`
+	Video    models.AnimeVideo `json:"Video"`
+	Status   Status            `json:"Status"`
+	Code     int               `json:"Code,omitempty"`
+	Reason   string            `json:"Reason,omitempty"`
+	Checked  time.Time         `json:"Checked"`
+}
+
+// Check requests the source of the video, with its referer when the scraper set one,
+// and tells whether the link still plays.
+func Check(ctx context.Context, video models.AnimeVideo) *Link {
+	link := &Link{Video: video}
+	link.Status, link.Code, link.Reason = check(ctx, video)
+	link.Checked = time.Now().UTC()
+	return link
+}
+
+func check(ctx context.Context, video models.AnimeVideo) (Status, int, string) {
+	endpoint, err := url.Parse(video.Source)
+	if err != nil || endpoint.Host == "" {
+		return Dead, 0, "invalid url"
+	}
+	if endpoint.Scheme == "" {
+		endpoint.Scheme = "https"
+	}
+
+	headers := map[string]string{
+		"Accept": "*/*",
+	}
+	if video.Referer != "" {
+		headers["Referer"] = video.Referer
+		if ref, err := url.Parse(video.Referer); err == nil && ref.Host != "" {
+			headers["Origin"] = ref.Scheme + "://" + ref.Host
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, linkTimeout)
+	defer cancel()
+
+	// pages are read to find the errors shown in the player, a HEAD is enough for files and
+	// only a host that rejects it gets a GET of the first byte
+	if !media(endpoint) {
+		reply, err := client.Probe(ctx, &client.Args{
+			Method:   http.MethodGet,
+			Endpoint: endpoint,
+			Headers:  headers,
+			Limit:    pageLimit,
+		})
+		if err != nil {
+			return failure(err)
+		}
+		return classify(reply)
+	}
+
+	reply, err := client.Probe(ctx, &client.Args{
+		Method:   http.MethodHead,
+		Endpoint: endpoint,
+		Headers:  headers,
+	})
+	if err == nil && rejected(reply.Status) {
+		headers["Range"] = "bytes=0-0"
+		reply, err = client.Probe(ctx, &client.Args{
+			Method:   http.MethodGet,
+			Endpoint: endpoint,
+			Headers:  headers,
+			Limit:    pageLimit,
+		})
+	}
+	if err != nil {
+		return failure(err)
+	}
+
+	return classify(reply)
+}
+
+// media reports whether the link is a media file rather than a player page.
+func media(link *url.URL) bool {
+	return slices.Contains(mediaExts, strings.ToLower(path.Ext(link.Path)))
+}
+
+// rejected reports whether the host refused the HEAD request itself.
+func rejected(status int) bool {
+	return status == http.StatusMethodNotAllowed || status == http.StatusNotImplemented || status == http.StatusForbidden
+}
+
+func failure(err error) (Status, int, string) {
+	if errors.Is(err, context.Canceled) {
+		return Unknown, 0, "canceled"
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return Unknown, 0, "timed out"
+	}
+
+	var dns *net.DNSError
+	if errors.As(err, &dns) && dns.IsNotFound {
+		return Dead, 0, "host does not exist"
+	}
+	var op *net.OpError
+	if errors.As(err, &op) && op.Op == "dial" && !op.Timeout() {
+		return Dead, 0, "connection refused"
+	}
+	return Unknown, 0, err.Error()
+}
+
+func classify(reply *client.Reply) (Status, int, string) {
+	var (
+		code = reply.Status
+		body = bytes.ToLower(reply.Body)
+	)
+
+	switch {
+	case code == http.StatusUnavailableForLegalReasons:
+		return Geo, code, "unavailable for legal reasons"
+	case code == http.StatusNotFound || code == http.StatusGone:
+		return Dead, code, http.StatusText(code)
+	}
+
+	// Cloudflare answers its interstitials with 403 or 503, a 200 page is the real one.
+	if reply.Header.Get("Cf-Mitigated") == "challenge" ||
+		(code == http.StatusForbidden || code == http.StatusServiceUnavailable) && contains(body, challengeMarks) != "" {
+		return Cloudflare, code, "challenge page"
+	}
+	if mark := contains(body, geoMarks); mark != "" {
+		return Geo, code, mark
+	}
+
+	if code >= http.StatusBadRequest {
+		return Unknown, code, http.StatusText(code)
+	}
+
+	if mark := contains(body, deadMarks); mark != "" {
+		return Dead, code, mark
+	}
+	return Alive, code, ""
+}
+
+func contains(body []byte, marks []string) string {
+	for _, v := range marks {
+		if bytes.Contains(body, []byte(v)) {
+			return v
+		}
+	}
+	return ""
+}
+
+// All checks the videos with at most parallel requests in flight, default is 8.
+func All(ctx context.Context, videos []models.AnimeVideo, parallel int) []*Link {
+	links := make([]*Link, len(videos))
+	for i, v := range videos {
+		links[i] = &Link{Video: v}
+	}
+	run(ctx, links, parallel)
+	return links
+}
+
+func run(ctx context.Context, links []*Link, parallel int) {
+	if parallel <= 0 {
+		parallel = 8
+	}
+
+	var (
+		wg    sync.WaitGroup
+		slots = make(chan struct{}, parallel)
+	)
+	for _, v := range links {
+		select {
+		case slots <- struct{}{}:
+		case <-ctx.Done():
+			v.Status, v.Reason = Unknown, "canceled"
+			continue
+		}
+
+		wg.Add(1)
+		go func(link *Link) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			link.Status, link.Code, link.Reason = check(ctx, link.Video)
+			link.Checked = time.Now().UTC()
+		}(v)
+	}
+	wg.Wait()
+}
+
+// Prune checks every video and download of the result, removes the dead ones from it
+// and returns the state of every link.
+func Prune(ctx context.Context, result *scrape.Result, parallel int) []*Link {
+	var links []*Link
+	for _, ep := range result.Episodes {
+		for _, v := range ep.Videos {
+			links = append(links, &Link{Provider: v.Provider, Episode: ep.Number, Video: v.Video})
+		}
+		for _, v := range ep.Download {
+			links = append(links, &Link{Provider: v.Provider, Episode: ep.Number, Download: true, Video: v.Video})
+		}
+	}
+	run(ctx, links, parallel)
+
+	dead := make(map[*Link]bool)
+	for _, v := range links {
+		if v.Status == Dead {
+			dead[v] = true
+		}
+	}
+
+	i := 0
+	for _, ep := range result.Episodes {
+		var videos, downloads []scrape.SourceVideo
+		for _, v := range ep.Videos {
+			if !dead[links[i]] {
+				videos = append(videos, v)
+			}
+			i++
+		}
+		for _, v := range ep.Download {
+			if !dead[links[i]] {
+				downloads = append(downloads, v)
+			}
+			i++
+		}
+		ep.Videos, ep.Download = videos, downloads
+	}
+
+	logger.Info("links were checked", "total", len(links), "dead", len(dead))
+	return links
+}
+
+// States returns whether each checked link is alive by analyze.ExtractSourceKey,
+// for analyze.VideoRank. Links in an unknown state are left out.
+func States(links []*Link) map[string]bool {
+	data := make(map[string]bool, len(links))
+	for _, v := range links {
+		switch v.Status {
+		case Alive:
+			data[analyze.ExtractSourceKey(v.Video.Source)] = true
+		case Dead:
+			data[analyze.ExtractSourceKey(v.Video.Source)] = false
+		}
+	}
+	return data
+}
@@ -0,0 +1,89 @@
+package verify
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/anicine/anicine-scraper/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestClassify(t *testing.T) {
+	cloudflare := http.Header{"Server": {"cloudflare"}, "Content-Type": {"text/html"}}
+
+	for _, v := range []struct {
+		name   string
+		status int
+		header http.Header
+		body   string
+		want   Status
+	}{
+		{"player", 200, nil, `<html><video src="/v.mp4"></video></html>`, Alive},
+		{"file", 206, http.Header{"Content-Type": {"video/mp4"}}, "", Alive},
+		{"challenge script on a real page", 200, cloudflare, `<title>Player</title><script src="/cdn-cgi/challenge-platform/h/b/orchestrate/jsch/v1"></script>`, Alive},
+		{"not found", 404, nil, "", Dead},
+		{"gone", 410, nil, "", Dead},
+		{"deleted in the player", 200, nil, `<div class="error">File was deleted</div>`, Dead},
+		{"not found in the player", 200, nil, `<h1>Video not found</h1>`, Dead},
+		{"legal reasons", 451, nil, "", Geo},
+		{"country", 200, nil, `<p>This video is not available in your country.</p>`, Geo},
+		{"region forbidden", 403, nil, `Sorry, this content is geo-restricted`, Geo},
+		{"interstitial", 403, cloudflare, `<html><head><title>Just a moment...</title></head></html>`, Cloudflare},
+		{"attention", 503, cloudflare, `<title>Attention Required! | Cloudflare</title>`, Cloudflare},
+		{"mitigated", 403, http.Header{"Cf-Mitigated": {"challenge"}}, "", Cloudflare},
+		{"forbidden", 403, nil, "", Unknown},
+		{"server error", 502, nil, "", Unknown},
+		{"rate limited", 429, nil, "", Unknown},
+	} {
+		t.Run(v.name, func(t *testing.T) {
+			header := v.header
+			if header == nil {
+				header = http.Header{}
+			}
+			status, code, _ := classify(&client.Reply{Status: v.status, Header: header, Body: []byte(v.body)})
+			assert.Equal(t, v.want, status)
+			assert.Equal(t, v.status, code)
+		})
+	}
+}
+
+func TestFailure(t *testing.T) {
+	for _, v := range []struct {
+		err  error
+		want Status
+	}{
+		{context.Canceled, Unknown},
+		{fmt.Errorf("probe: %w", context.DeadlineExceeded), Unknown},
+		{&net.DNSError{Err: "no such host", Name: "dead.example", IsNotFound: true}, Dead},
+		{&net.OpError{Op: "dial", Err: errors.New("connection refused")}, Dead},
+		{&net.OpError{Op: "read", Err: errors.New("connection reset by peer")}, Unknown},
+		{errors.New("tls: handshake failure"), Unknown},
+	} {
+		status, _, reason := failure(v.err)
+		assert.Equal(t, v.want, status, v.err.Error())
+		assert.NotEmpty(t, reason)
+	}
+}
+
+func TestMedia(t *testing.T) {
+	for raw, want := range map[string]bool{
+		"https://cdn.example.com/v/frieren-01.mp4":           true,
+		"https://cdn.example.com/hls/master.M3U8?token=abc":  true,
+		"https://cdn.example.com/dash/manifest.mpd":          true,
+		"https://streamwish.to/e/abc123":                     false,
+		"https://dood.wf/e/abc123.html":                      false,
+		"https://mega.nz/embed/abc#key":                      false,
+		"https://www.mp4upload.com/embed-abc123.html":        false,
+		"https://cdn.example.com/download?file=frieren.mp4":  false,
+		"https://ok.ru/videoembed/123456789":                 false,
+		"https://cdn.example.com/segments/frieren-01-001.ts": true,
+	} {
+		u, _ := url.Parse(raw)
+		assert.Equal(t, want, media(u), raw)
+	}
+}